@@ -1,14 +1,24 @@
 package main
 
 import (
-	_ "evm-tx-watcher/docs"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	nethttp "net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
 	"evm-tx-watcher/db"
+	_ "evm-tx-watcher/docs"
+	"evm-tx-watcher/internal/buildinfo"
+	"evm-tx-watcher/internal/cache"
 	"evm-tx-watcher/internal/config"
 	"evm-tx-watcher/internal/http"
 	"evm-tx-watcher/internal/util"
 	"evm-tx-watcher/internal/validator"
-	"fmt"
-	"log"
 
 	echoSwagger "github.com/swaggo/echo-swagger"
 )
@@ -18,6 +28,9 @@ import (
 // @description     A simple REST API service for monitoring Ethereum wallet addresses and getting notified when transactions occur.
 // @host            localhost:8080
 // @BasePath        /api/v1
+// @securityDefinitions.apikey ApiKeyAuth
+// @in                          header
+// @name                        X-API-Key
 
 func main() {
 	// Load configuration
@@ -28,7 +41,8 @@ func main() {
 
 	// Initialize logger with config
 	logger := util.NewLogger(cfg.LogLevel, cfg.LogFormat)
-	logger.Info("Starting EVM Transaction Watcher server...")
+	build := buildinfo.Get()
+	logger.Infof("Starting EVM Transaction Watcher server %s (%s)...", build.Version, build.Commit)
 
 	// Initialize request validator
 	v := validator.NewValidator()
@@ -40,20 +54,49 @@ func main() {
 	}
 	defer db.Close()
 
+	// Initialize Redis connection
+	redisClient, err := cache.NewRedisClient(&cfg.Redis)
+	if err != nil {
+		logger.Fatalf("Error initializing redis: %v", err)
+	}
+	defer redisClient.Close()
+
 	// Create server address
 	addr := fmt.Sprintf(":%s", cfg.AppPort)
 	logger.Infof("Server will listen on %s", addr)
 
+	// Cancelled on shutdown, stops the stream hub and ends open streams
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	// Initialize Echo router
-	e := http.NewRouter(cfg, db, logger, v)
+	e := http.NewRouter(ctx, cfg, db, redisClient, logger, v)
 	e.Validator = v
 
 	// Swagger documentation
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 
+	// handle signals
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ch := make(chan os.Signal, 1)
+		signal.Notify(ch, syscall.SIGINT, syscall.SIGTERM)
+		<-ch
+		logger.Info("shutting down server...")
+		cancel()
+
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer shutdownCancel()
+		if err := e.Shutdown(shutdownCtx); err != nil {
+			logger.WithError(err).Error("Error shutting down server")
+		}
+	}()
+
 	// Start server
 	logger.Infof("Starting server on %s", addr)
-	if err := e.Start(addr); err != nil {
+	if err := e.Start(addr); err != nil && !errors.Is(err, nethttp.ErrServerClosed) {
 		logger.Fatalf("Error starting server: %v", err)
 	}
+	<-stopped
 }
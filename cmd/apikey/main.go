@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"evm-tx-watcher/db"
+	"evm-tx-watcher/internal/config"
+	"evm-tx-watcher/internal/dto"
+	"evm-tx-watcher/internal/repository"
+	"evm-tx-watcher/internal/service"
+
+	"github.com/google/uuid"
+)
+
+const usage = `usage:
+  apikey create -name NAME [-user USER_ID]   issue a key, for a new user unless -user is given
+  apikey list -user USER_ID                  list the keys of a user
+  apikey revoke -user USER_ID -id KEY_ID     revoke a key`
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal(usage)
+	}
+
+	flags := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	name := flags.String("name", "", "name of the key, e.g. the system that uses it")
+	user := flags.String("user", "", "user ID the key acts for")
+	id := flags.String("id", "", "ID of the key to revoke")
+	_ = flags.Parse(os.Args[2:])
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
+
+	database, err := db.InitDB(&cfg.DB)
+	if err != nil {
+		log.Fatalf("failed to initialize database: %v", err)
+	}
+	defer database.Close()
+
+	apiKeys := service.NewAPIKeyService(repository.NewUnitOfWork(database), repository.NewAPIKeyRepository(database))
+	ctx := context.Background()
+
+	switch os.Args[1] {
+	case "create":
+		if *name == "" {
+			log.Fatal(usage)
+		}
+		userID := uuid.New()
+		if *user != "" {
+			userID = parseUUID(*user, "user")
+		}
+		key, appErr := apiKeys.Create(ctx, userID, &dto.CreateAPIKeyRequest{Name: *name})
+		if appErr != nil {
+			log.Fatalf("failed to create api key: %v", appErr)
+		}
+		fmt.Printf("user:    %s\nkey id:  %s\napi key: %s\n", key.UserID, key.ID, key.Key)
+		fmt.Println("Store the key now, it cannot be shown again.")
+
+	case "list":
+		if *user == "" {
+			log.Fatal(usage)
+		}
+		keys, appErr := apiKeys.List(ctx, parseUUID(*user, "user"))
+		if appErr != nil {
+			log.Fatalf("failed to list api keys: %v", appErr)
+		}
+		for _, key := range keys {
+			status := "active"
+			if key.RevokedAt != nil {
+				status = "revoked"
+			}
+			fmt.Printf("%s  %s...  %-8s %s\n", key.ID, key.KeyPrefix, status, key.Name)
+		}
+
+	case "revoke":
+		if *user == "" || *id == "" {
+			log.Fatal(usage)
+		}
+		if appErr := apiKeys.Revoke(ctx, parseUUID(*user, "user"), parseUUID(*id, "key")); appErr != nil {
+			log.Fatalf("failed to revoke api key: %v", appErr)
+		}
+		fmt.Println("revoked")
+
+	default:
+		log.Fatal(usage)
+	}
+}
+
+func parseUUID(value, what string) uuid.UUID {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		log.Fatalf("invalid %s ID %q", what, value)
+	}
+	return id
+}
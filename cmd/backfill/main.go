@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"evm-tx-watcher/internal/app"
+	"evm-tx-watcher/internal/backfill"
+	"evm-tx-watcher/internal/config"
+	"evm-tx-watcher/internal/util"
+)
+
+func main() {
+	network := flag.String("network", "", "network name, e.g. ethereum-sepolia")
+	addresses := flag.String("addresses", "", "comma-separated addresses to backfill")
+	all := flag.Bool("all", false, "backfill every address watched on the network")
+	from := flag.Uint64("from", 0, "first block of the range")
+	to := flag.Uint64("to", 0, "last block of the range, must be confirmed")
+	chunkSize := flag.Uint64("chunk-size", 1000, "blocks per eth_getLogs range and progress checkpoint")
+	concurrency := flag.Int("concurrency", 4, "chunks processed in parallel")
+	notify := flag.Bool("notify", false, "send transaction.confirmed webhooks for backfilled transactions")
+	flag.Parse()
+
+	if *network == "" || *to == 0 || *chunkSize == 0 || (*addresses == "") == !*all {
+		flag.Usage()
+		log.Fatal("-network, -to and exactly one of -addresses or -all are required")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
+
+	logger := util.NewLogger(cfg.LogLevel, cfg.LogFormat)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// handle signals
+	go func() {
+		ch := make(chan os.Signal, 1)
+		signal.Notify(ch, syscall.SIGINT, syscall.SIGTERM)
+		<-ch
+		logger.Info("stopping backfill...")
+		cancel()
+	}()
+
+	opts := backfill.Options{
+		From:        *from,
+		To:          *to,
+		ChunkSize:   *chunkSize,
+		Concurrency: *concurrency,
+		Notify:      *notify,
+	}
+	if *addresses != "" {
+		for _, address := range strings.Split(*addresses, ",") {
+			if address = strings.TrimSpace(address); address != "" {
+				opts.Addresses = append(opts.Addresses, address)
+			}
+		}
+	}
+
+	if err := app.RunBackfill(ctx, cfg, logger, *network, opts); err != nil {
+		logger.WithError(err).Error("backfill exited with error")
+		os.Exit(1)
+	}
+}
@@ -1,6 +1,8 @@
 package db
 
 import (
+	"context"
+	"database/sql"
 	"evm-tx-watcher/internal/config"
 	"fmt"
 
@@ -19,3 +21,18 @@ func InitDB(cfg *config.DatabaseConfig) (*sqlx.DB, error) {
 
 	return db, nil
 }
+
+// MigrationVersion returns the schema version recorded by golang-migrate and
+// whether the last migration failed halfway
+func MigrationVersion(ctx context.Context, db *sqlx.DB) (uint, bool, error) {
+	var version uint
+	var dirty bool
+	err := db.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if err == sql.ErrNoRows {
+		return 0, false, fmt.Errorf("no migration has been applied")
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read migration version: %w", err)
+	}
+	return version, dirty, nil
+}
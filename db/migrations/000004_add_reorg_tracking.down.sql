@@ -0,0 +1,10 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_transactions_chain_block_hash;
+DROP INDEX IF EXISTS idx_transactions_chain_hash_live;
+
+-- Restore global hash uniqueness (fails if re-included transactions exist)
+ALTER TABLE transactions ADD CONSTRAINT transactions_hash_key UNIQUE (hash);
+
+-- Drop columns
+ALTER TABLE token_transfers DROP COLUMN IF EXISTS removed;
+ALTER TABLE transactions DROP COLUMN IF EXISTS removed;
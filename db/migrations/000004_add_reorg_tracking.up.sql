@@ -0,0 +1,13 @@
+-- Rows from blocks that left the canonical chain are marked instead of deleted,
+-- so webhook deliveries that already referenced them stay intact
+ALTER TABLE transactions ADD COLUMN removed BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE token_transfers ADD COLUMN removed BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- A reorged transaction can be re-included in another block,
+-- so its hash is only unique among the live rows of a chain
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_hash_key;
+CREATE UNIQUE INDEX idx_transactions_chain_hash_live
+    ON transactions(chain_id, hash) WHERE removed = FALSE;
+
+-- Lookup by block hash when a block is reorged out
+CREATE INDEX idx_transactions_chain_block_hash ON transactions(chain_id, block_hash);
@@ -0,0 +1,5 @@
+-- Drop trigger
+DROP TRIGGER IF EXISTS trg_set_block_cursors_updated_at ON block_cursors;
+
+-- Drop table
+DROP TABLE IF EXISTS block_cursors;
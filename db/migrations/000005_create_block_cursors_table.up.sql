@@ -0,0 +1,13 @@
+-- Last block the worker fully processed per network
+CREATE TABLE block_cursors (
+    chain_id BIGINT PRIMARY KEY,
+    network TEXT NOT NULL,
+    block_number BIGINT NOT NULL,
+    block_hash TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TRIGGER trg_set_block_cursors_updated_at
+BEFORE UPDATE ON block_cursors
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_token_transfers_token_address_token_id;
+
+DELETE FROM token_transfers WHERE standard <> 'erc20';
+
+ALTER TABLE token_transfers DROP CONSTRAINT IF EXISTS token_transfers_transaction_id_log_index_batch_index_key;
+ALTER TABLE token_transfers ADD CONSTRAINT token_transfers_transaction_id_log_index_key
+    UNIQUE (transaction_id, log_index);
+
+ALTER TABLE token_transfers DROP COLUMN IF EXISTS batch_index;
+ALTER TABLE token_transfers DROP COLUMN IF EXISTS operator_address;
+ALTER TABLE token_transfers DROP COLUMN IF EXISTS token_id;
+ALTER TABLE token_transfers DROP COLUMN IF EXISTS standard;
@@ -0,0 +1,14 @@
+-- token_transfers also stores ERC-721 and ERC-1155 transfers, discriminated by standard
+ALTER TABLE token_transfers ADD COLUMN standard TEXT NOT NULL DEFAULT 'erc20';
+ALTER TABLE token_transfers ADD COLUMN token_id NUMERIC(78,0); -- ERC-721 / ERC-1155 token ID
+ALTER TABLE token_transfers ADD COLUMN operator_address TEXT;  -- ERC-1155 operator
+ALTER TABLE token_transfers ADD COLUMN batch_index INT NOT NULL DEFAULT 0;
+
+-- One ERC-1155 TransferBatch log carries several transfers
+ALTER TABLE token_transfers DROP CONSTRAINT IF EXISTS token_transfers_transaction_id_log_index_key;
+ALTER TABLE token_transfers ADD CONSTRAINT token_transfers_transaction_id_log_index_batch_index_key
+    UNIQUE (transaction_id, log_index, batch_index);
+
+-- Lookup of a single NFT's history
+CREATE INDEX idx_token_transfers_token_address_token_id ON token_transfers(token_address, token_id)
+    WHERE token_id IS NOT NULL;
@@ -0,0 +1,5 @@
+-- Drop trigger
+DROP TRIGGER IF EXISTS trg_set_tokens_updated_at ON tokens;
+
+-- Drop table
+DROP TABLE IF EXISTS tokens;
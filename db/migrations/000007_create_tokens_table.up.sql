@@ -0,0 +1,16 @@
+-- Token metadata resolved through eth_call, one row per contract and chain
+CREATE TABLE tokens (
+    chain_id BIGINT NOT NULL,
+    address TEXT NOT NULL,
+    decimals INT,   -- NULL when decimals() is not implemented
+    symbol TEXT,
+    name TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (chain_id, address)
+);
+
+CREATE TRIGGER trg_set_tokens_updated_at
+BEFORE UPDATE ON tokens
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
@@ -0,0 +1,5 @@
+-- Drop trigger
+DROP TRIGGER IF EXISTS trg_set_internal_transfers_updated_at ON internal_transfers;
+
+-- Drop table
+DROP TABLE IF EXISTS internal_transfers;
@@ -0,0 +1,25 @@
+-- Native value moved by calls inside a transaction, from debug_traceBlockByNumber
+CREATE TABLE internal_transfers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    trace_index INT NOT NULL,
+    call_type TEXT NOT NULL,
+    from_address TEXT NOT NULL,
+    to_address TEXT NOT NULL,
+    value NUMERIC(78,0) NOT NULL,
+    depth INT NOT NULL,
+    removed BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE(transaction_id, trace_index)
+);
+
+-- Indexes for internal transfers
+CREATE INDEX idx_internal_transfers_transaction_id ON internal_transfers(transaction_id);
+CREATE INDEX idx_internal_transfers_from_address ON internal_transfers(from_address);
+CREATE INDEX idx_internal_transfers_to_address ON internal_transfers(to_address);
+
+CREATE TRIGGER trg_set_internal_transfers_updated_at
+BEFORE UPDATE ON internal_transfers
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
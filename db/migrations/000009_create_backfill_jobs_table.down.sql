@@ -0,0 +1,5 @@
+-- Drop trigger
+DROP TRIGGER IF EXISTS trg_set_backfill_jobs_updated_at ON backfill_jobs;
+
+-- Drop table
+DROP TABLE IF EXISTS backfill_jobs;
@@ -0,0 +1,19 @@
+-- Progress of historical backfills, so an interrupted run resumes where it stopped
+CREATE TABLE backfill_jobs (
+    id TEXT PRIMARY KEY,
+    chain_id BIGINT NOT NULL,
+    network TEXT NOT NULL,
+    from_block BIGINT NOT NULL,
+    to_block BIGINT NOT NULL,
+    next_block BIGINT NOT NULL,
+    address_count INT NOT NULL,
+    stored_transactions BIGINT NOT NULL DEFAULT 0,
+    completed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TRIGGER trg_set_backfill_jobs_updated_at
+BEFORE UPDATE ON backfill_jobs
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
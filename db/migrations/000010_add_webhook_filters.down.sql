@@ -0,0 +1,3 @@
+-- Drop columns
+ALTER TABLE webhooks DROP COLUMN IF EXISTS events;
+ALTER TABLE webhooks DROP COLUMN IF EXISTS is_active;
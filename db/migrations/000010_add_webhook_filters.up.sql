@@ -0,0 +1,6 @@
+-- Webhooks can be paused without deleting them
+ALTER TABLE webhooks ADD COLUMN is_active BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- Activity a webhook is notified about: incoming, outgoing, token, native.
+-- Existing webhooks keep receiving everything.
+ALTER TABLE webhooks ADD COLUMN events TEXT[] NOT NULL DEFAULT '{incoming,outgoing,token,native}';
@@ -0,0 +1,2 @@
+-- Drop index
+DROP INDEX IF EXISTS idx_transactions_chain_block_txindex;
@@ -0,0 +1,3 @@
+-- Keyset pagination of the transactions API, newest first within a chain
+CREATE INDEX idx_transactions_chain_block_txindex
+    ON transactions(chain_id, block_number DESC, transaction_index DESC, id DESC);
@@ -0,0 +1,5 @@
+-- Drop index
+DROP INDEX IF EXISTS idx_webhook_deliveries_webhook_created;
+
+-- Drop table
+DROP TABLE IF EXISTS webhook_delivery_attempts;
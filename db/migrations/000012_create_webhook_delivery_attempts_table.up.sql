@@ -0,0 +1,16 @@
+-- Every HTTP attempt of a webhook delivery, including manual redeliveries;
+-- the delivery row only keeps the outcome of the latest one
+CREATE TABLE webhook_delivery_attempts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    delivery_id UUID NOT NULL REFERENCES webhook_deliveries(id) ON DELETE CASCADE,
+    http_status_code INT,
+    response_body TEXT,
+    error_message TEXT,
+    duration_ms BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_webhook_delivery_attempts_delivery_id ON webhook_delivery_attempts(delivery_id, created_at);
+
+-- Delivery history per webhook, newest first
+CREATE INDEX idx_webhook_deliveries_webhook_created ON webhook_deliveries(webhook_id, created_at DESC, id DESC);
@@ -0,0 +1,12 @@
+-- Drop index
+DROP INDEX IF EXISTS idx_addresses_user_address_chain;
+
+-- Addresses may have no owner again; legacy ones keep the legacy user
+ALTER TABLE addresses ALTER COLUMN user_id DROP NOT NULL;
+
+-- Restore global address uniqueness
+CREATE UNIQUE INDEX IF NOT EXISTS idx_addresses_address_chain
+    ON addresses (address, chain_id);
+
+-- Drop table
+DROP TABLE IF EXISTS api_keys;
@@ -0,0 +1,24 @@
+-- API keys, stored as SHA-256 hashes; each key acts for one user (tenant)
+CREATE TABLE api_keys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    key_prefix VARCHAR(16) NOT NULL,
+    key_hash CHAR(64) NOT NULL,
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX idx_api_keys_key_hash ON api_keys(key_hash);
+CREATE INDEX idx_api_keys_user_id ON api_keys(user_id);
+
+-- Addresses registered before API keys have no owner. They move to the legacy
+-- user 00000000-0000-0000-0000-000000000001; issue a key for that user to manage them.
+UPDATE addresses SET user_id = '00000000-0000-0000-0000-000000000001' WHERE user_id IS NULL;
+ALTER TABLE addresses ALTER COLUMN user_id SET NOT NULL;
+
+-- Addresses are unique per tenant instead of globally, in any letter case.
+-- Legacy addresses differing only in case have to be merged before this runs.
+DROP INDEX IF EXISTS idx_addresses_address_chain;
+CREATE UNIQUE INDEX idx_addresses_user_address_chain ON addresses(user_id, LOWER(address), chain_id);
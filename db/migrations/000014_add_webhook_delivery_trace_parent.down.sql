@@ -0,0 +1,2 @@
+-- Drop column
+ALTER TABLE webhook_deliveries DROP COLUMN IF EXISTS trace_parent;
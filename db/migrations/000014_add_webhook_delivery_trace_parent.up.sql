@@ -0,0 +1,3 @@
+-- W3C traceparent of the span that created a delivery, so its sends join the
+-- trace of the block that caused it
+ALTER TABLE webhook_deliveries ADD COLUMN trace_parent VARCHAR(55);
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"evm-tx-watcher/db"
+	"evm-tx-watcher/internal/backfill"
+	"evm-tx-watcher/internal/blockchain/client"
+	"evm-tx-watcher/internal/cache"
+	"evm-tx-watcher/internal/config"
+	"evm-tx-watcher/internal/processor"
+	"evm-tx-watcher/internal/repository"
+	"evm-tx-watcher/internal/stream"
+	"evm-tx-watcher/internal/token"
+	"evm-tx-watcher/internal/util"
+)
+
+// RunBackfill imports the history of watched addresses on one network
+func RunBackfill(ctx context.Context, cfg *config.Config, logger *util.Logger, network string, opts backfill.Options) error {
+	networkConfig, ok := cfg.Networks[network]
+	if !ok {
+		return fmt.Errorf("unknown network %q", network)
+	}
+
+	database, err := db.InitDB(&cfg.DB)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer database.Close()
+
+	redisClient, err := cache.NewRedisClient(&cfg.Redis)
+	if err != nil {
+		return fmt.Errorf("failed to initialize redis: %w", err)
+	}
+	defer redisClient.Close()
+
+	blockchainClient, err := client.New(networkConfig, logger)
+	if err != nil {
+		return fmt.Errorf("failed to create client for %s: %w", network, err)
+	}
+	defer blockchainClient.Close()
+
+	unitOfWork := repository.NewUnitOfWork(database)
+	tokens := token.NewMetadataService(unitOfWork, repository.NewTokenRepository(database), redisClient, logger)
+	tokens.AddChain(networkConfig.ChainID, blockchainClient)
+
+	// Backfilled rows go through the same repositories as the worker's
+	proc := processor.New(
+		logger,
+		unitOfWork,
+		repository.NewAddressRepository(database),
+		repository.NewTransactionRepository(database),
+		repository.NewTokenTransferRepository(database),
+		repository.NewInternalTransferRepository(database),
+		repository.NewWebhookDeliveryRepository(database),
+		repository.NewBlockCursorRepository(database),
+		redisClient,
+		tokens,
+		stream.NewPublisher(redisClient, cfg.Stream.HistorySize, logger),
+	)
+	if err := proc.LoadWatchedAddresses(ctx); err != nil {
+		return fmt.Errorf("failed to load watched addresses: %w", err)
+	}
+
+	backfiller := backfill.New(blockchainClient, proc, unitOfWork, repository.NewBackfillJobRepository(database), logger)
+	return backfiller.Run(ctx, opts)
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"evm-tx-watcher/db"
+	"evm-tx-watcher/internal/buildinfo"
+	"evm-tx-watcher/internal/cache"
+	"evm-tx-watcher/internal/config"
+	"evm-tx-watcher/internal/metrics"
+	"evm-tx-watcher/internal/repository"
+	"evm-tx-watcher/internal/tracing"
+	"evm-tx-watcher/internal/util"
+	"evm-tx-watcher/internal/webhook"
+)
+
+func RunDispatcher(ctx context.Context, cfg *config.Config, logger *util.Logger) error {
+	build := buildinfo.Get()
+	logger.Infof("Starting EVM Transaction Watcher Webhook Dispatcher %s (%s)", build.Version, build.Commit)
+
+	// Initialize database connection
+	database, err := db.InitDB(&cfg.DB)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer database.Close()
+
+	// Initialize Redis connection
+	redisClient, err := cache.NewRedisClient(&cfg.Redis)
+	if err != nil {
+		return fmt.Errorf("failed to initialize redis: %w", err)
+	}
+	defer redisClient.Close()
+
+	shutdownTracing, err := tracing.Init(ctx, cfg.Tracing, "evm-tx-watcher-dispatcher")
+	if err != nil {
+		return fmt.Errorf("failed to initialize tracing: %w", err)
+	}
+	defer func() {
+		// ctx is cancelled by now, flushing the last spans gets its own deadline
+		flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			logger.WithError(err).Warn("Failed to flush traces")
+		}
+	}()
+
+	unitOfWork := repository.NewUnitOfWork(database)
+	deliveryRepo := repository.NewWebhookDeliveryRepository(database)
+
+	dispatcher := webhook.NewDispatcher(
+		cfg.Dispatcher,
+		unitOfWork,
+		deliveryRepo,
+		repository.NewWebhookDeliveryAttemptRepository(database),
+		repository.NewWebhookRepository(database),
+		redisClient,
+		logger,
+	)
+	scheduler := webhook.NewRetryScheduler(cfg.Dispatcher, dispatcher, unitOfWork, deliveryRepo, logger)
+
+	metrics.RegisterWebhookQueue(func() (int64, error) {
+		ctx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		return redisClient.GetQueueLength(ctx)
+	})
+	metricsServer := metrics.NewServer(cfg.Dispatcher.MetricsPort, logger)
+
+	// Stop the other loops as well if the dispatcher exits on its own
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		metricsServer.Start(ctx)
+	}()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := scheduler.Start(ctx); err != nil {
+			logger.WithError(err).Error("Retry scheduler stopped with error")
+		}
+	}()
+
+	err = dispatcher.Start(ctx)
+	cancel()
+	wg.Wait()
+	return err
+}
@@ -0,0 +1,178 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"evm-tx-watcher/internal/blockchain/client"
+	"evm-tx-watcher/internal/blockchain/watcher"
+	"evm-tx-watcher/internal/health"
+	"evm-tx-watcher/internal/http/handler"
+	"evm-tx-watcher/internal/util"
+
+	"github.com/labstack/echo/v4"
+	echomiddleware "github.com/labstack/echo/v4/middleware"
+)
+
+// NetworkEndpoints is the RPC endpoint health of one network
+type NetworkEndpoints struct {
+	Network   string                  `json:"network"`
+	ChainID   int64                   `json:"chain_id"`
+	Endpoints []client.EndpointHealth `json:"endpoints"`
+}
+
+// NetworkStatus is the progress of one network from its RPC endpoints to the processor
+type NetworkStatus struct {
+	Network              string    `json:"network"`
+	ChainID              int64     `json:"chain_id"`
+	Endpoint             string    `json:"endpoint"`                        // endpoint calls go to first
+	SubscriptionEndpoint string    `json:"subscription_endpoint,omitempty"` // endpoint holding the new heads subscription
+	HeadSource           string    `json:"head_source"`
+	Head                 uint64    `json:"head"`
+	HeadSeenAt           time.Time `json:"head_seen_at"`
+	LastConfirmed        uint64    `json:"last_confirmed"` // released by the watcher
+	LastProcessed        uint64    `json:"last_processed"` // finished by the processor
+	Lag                  uint64    `json:"lag"`            // blocks between the head and the last processed block
+}
+
+// statusServer exposes the worker's internal state and health probes to operators
+type statusServer struct {
+	echo   *echo.Echo
+	port   string
+	logger *util.Logger
+
+	mu       sync.RWMutex
+	clients  map[string]*client.Client
+	watchers map[string]*watcher.Watcher
+	queues   []*watcher.BlockQueue
+}
+
+func newStatusServer(port string, checker *health.Checker, logger *util.Logger) *statusServer {
+	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
+	e.Use(echomiddleware.Recover())
+
+	s := &statusServer{
+		echo:     e,
+		port:     port,
+		logger:   logger,
+		clients:  make(map[string]*client.Client),
+		watchers: make(map[string]*watcher.Watcher),
+	}
+
+	healthHandler := handler.NewHealthHandler(checker, "evm-tx-watcher-worker")
+	e.GET("/health/live", healthHandler.Live)
+	e.GET("/health/ready", healthHandler.Ready)
+	e.GET("/status/endpoints", s.endpoints)
+	e.GET("/status/queues", s.queueStats)
+	e.GET("/status/networks", s.networks)
+
+	return s
+}
+
+// addClient registers a network's client so its endpoints are reported
+func (s *statusServer) addClient(c *client.Client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clients[c.NetworkConfig.Name] = c
+}
+
+// addWatcher registers a network's watcher so its progress is reported
+func (s *statusServer) addWatcher(network string, w *watcher.Watcher) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.watchers[network] = w
+}
+
+// addQueue registers a network's block queue so its depth and backpressure are reported
+func (s *statusServer) addQueue(q *watcher.BlockQueue) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queues = append(s.queues, q)
+}
+
+// Start serves until ctx is cancelled
+func (s *statusServer) Start(ctx context.Context) {
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = s.echo.Shutdown(shutdownCtx)
+	}()
+
+	s.logger.Infof("[Status] Listening on :%s", s.port)
+	if err := s.echo.Start(":" + s.port); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		s.logger.WithError(err).Error("[Status] Server stopped")
+	}
+}
+
+func (s *statusServer) endpoints(c echo.Context) error {
+	s.mu.RLock()
+	networks := make([]NetworkEndpoints, 0, len(s.clients))
+	for name, cl := range s.clients {
+		networks = append(networks, NetworkEndpoints{
+			Network:   name,
+			ChainID:   cl.NetworkConfig.ChainID,
+			Endpoints: cl.EndpointHealth(),
+		})
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(networks, func(i, j int) bool { return networks[i].Network < networks[j].Network })
+	return c.JSON(http.StatusOK, networks)
+}
+
+func (s *statusServer) queueStats(c echo.Context) error {
+	s.mu.RLock()
+	stats := make([]watcher.QueueStats, 0, len(s.queues))
+	for _, q := range s.queues {
+		stats = append(stats, q.Stats())
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Network < stats[j].Network })
+	return c.JSON(http.StatusOK, stats)
+}
+
+func (s *statusServer) networks(c echo.Context) error {
+	s.mu.RLock()
+	processed := make(map[string]uint64, len(s.queues))
+	for _, q := range s.queues {
+		processed[q.Network()] = q.Stats().LastProcessed
+	}
+
+	networks := make([]NetworkStatus, 0, len(s.clients))
+	for name, cl := range s.clients {
+		status := NetworkStatus{
+			Network:       name,
+			ChainID:       cl.NetworkConfig.ChainID,
+			Endpoint:      cl.PreferredEndpoint(),
+			LastProcessed: processed[name],
+		}
+		for _, ep := range cl.EndpointHealth() {
+			if ep.Subscribed {
+				status.SubscriptionEndpoint = ep.URL
+			}
+		}
+		if w, ok := s.watchers[name]; ok {
+			progress := w.Status()
+			status.HeadSource = progress.HeadSource
+			status.Head = progress.Head
+			status.HeadSeenAt = progress.HeadSeenAt
+			status.LastConfirmed = progress.Confirmed
+		}
+		if status.LastProcessed > 0 && status.Head > status.LastProcessed {
+			status.Lag = status.Head - status.LastProcessed
+		}
+		networks = append(networks, status)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(networks, func(i, j int) bool { return networks[i].Network < networks[j].Network })
+	return c.JSON(http.StatusOK, networks)
+}
@@ -2,25 +2,84 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"sync"
+	"time"
 
+	"evm-tx-watcher/db"
 	"evm-tx-watcher/internal/blockchain/client"
 	"evm-tx-watcher/internal/blockchain/watcher"
+	"evm-tx-watcher/internal/buildinfo"
+	"evm-tx-watcher/internal/cache"
 	"evm-tx-watcher/internal/config"
+	"evm-tx-watcher/internal/health"
+	"evm-tx-watcher/internal/metrics"
 	"evm-tx-watcher/internal/processor"
+	"evm-tx-watcher/internal/repository"
+	"evm-tx-watcher/internal/stream"
+	"evm-tx-watcher/internal/token"
+	"evm-tx-watcher/internal/tracing"
 	"evm-tx-watcher/internal/util"
-
-	"github.com/ethereum/go-ethereum/core/types"
 )
 
 func RunWorker(ctx context.Context, cfg *config.Config, logger *util.Logger) error {
-	logger.Info("Starting EVM Transaction Watcher Worker")
+	build := buildinfo.Get()
+	logger.Infof("Starting EVM Transaction Watcher Worker %s (%s)", build.Version, build.Commit)
 
-	var wg sync.WaitGroup
-	blockChan := make(chan *types.Block, 50)
+	// Initialize database connection
+	database, err := db.InitDB(&cfg.DB)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer database.Close()
+
+	// Initialize Redis connection
+	redisClient, err := cache.NewRedisClient(&cfg.Redis)
+	if err != nil {
+		return fmt.Errorf("failed to initialize redis: %w", err)
+	}
+	defer redisClient.Close()
+
+	shutdownTracing, err := tracing.Init(ctx, cfg.Tracing, "evm-tx-watcher-worker")
+	if err != nil {
+		return fmt.Errorf("failed to initialize tracing: %w", err)
+	}
+	defer func() {
+		// ctx is cancelled by now, flushing the last spans gets its own deadline
+		flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			logger.WithError(err).Warn("Failed to flush traces")
+		}
+	}()
 
-	// Initialize simple processor
-	proc := processor.New(logger)
+	unitOfWork := repository.NewUnitOfWork(database)
+	cursorRepo := repository.NewBlockCursorRepository(database)
+	tokens := token.NewMetadataService(unitOfWork, repository.NewTokenRepository(database), redisClient, logger)
+
+	status := newStatusServer(cfg.Worker.HTTPPort, health.NewChecker(database, redisClient), logger)
+	metricsServer := metrics.NewServer(cfg.Worker.MetricsPort, logger)
+
+	var wg sync.WaitGroup
+	var queues []*watcher.BlockQueue
+
+	// Initialize block processor
+	proc := processor.New(
+		logger,
+		unitOfWork,
+		repository.NewAddressRepository(database),
+		repository.NewTransactionRepository(database),
+		repository.NewTokenTransferRepository(database),
+		repository.NewInternalTransferRepository(database),
+		repository.NewWebhookDeliveryRepository(database),
+		cursorRepo,
+		redisClient,
+		tokens,
+		stream.NewPublisher(redisClient, cfg.Stream.HistorySize, logger),
+	)
+	if err := proc.LoadWatchedAddresses(ctx); err != nil {
+		return fmt.Errorf("failed to load watched addresses: %w", err)
+	}
 
 	// Start blockchain watchers for each network
 	for _, networkConfig := range cfg.Networks {
@@ -33,8 +92,18 @@ func RunWorker(ctx context.Context, cfg *config.Config, logger *util.Logger) err
 			continue // Skip this network, don't fail entire worker
 		}
 
-		// Create watcher with simple parameters
-		blockWatcher := watcher.New(blockchainClient, networkConfig, 5, logger)
+		status.addClient(blockchainClient)
+		tokens.AddChain(networkConfig.ChainID, blockchainClient)
+
+		// Each network gets its own queue so a busy chain cannot starve the others
+		queue := watcher.NewBlockQueue(networkConfig.Name, cfg.Worker.BlockQueueSize)
+		queues = append(queues, queue)
+		status.addQueue(queue)
+		metrics.RegisterBlockQueue(networkConfig.Name, func() int { return queue.Stats().Depth })
+
+		// Create watcher, confirmations follow the network's policy
+		blockWatcher := watcher.New(blockchainClient, cursorRepo, networkConfig, cfg.Worker.MaxCatchUpBlocks, logger)
+		status.addWatcher(networkConfig.Name, blockWatcher)
 
 		// Start watcher in goroutine
 		wg.Add(1)
@@ -44,42 +113,37 @@ func RunWorker(ctx context.Context, cfg *config.Config, logger *util.Logger) err
 
 			logger.Infof("Starting watcher for %s", network.Name)
 
-			if err := blockWatcher.Start(ctx, blockChan); err != nil && ctx.Err() == nil {
+			if err := blockWatcher.Start(ctx, queue); err != nil && ctx.Err() == nil {
 				logger.WithError(err).Errorf("Watcher %s stopped with error", network.Name)
 			}
 		}(networkConfig, blockchainClient)
 	}
 
-	// Start block processor
+	// Start block processor pool
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		logger.Info("Starting block processor")
-
-		for {
-			select {
-			case <-ctx.Done():
-				logger.Info("Block processor stopped")
-				return
-			case block := <-blockChan:
-				if block != nil {
-					if err := proc.HandleBlock(ctx, block); err != nil {
-						logger.WithError(err).Error("Failed to process block")
-					}
-				}
-			}
-		}
+		processor.NewPool(proc, cfg.Worker.ProcessorWorkers).Run(ctx, queues)
+	}()
+
+	// Start operator status server
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		status.Start(ctx)
 	}()
 
-	// Graceful shutdown
+	// Start Prometheus metrics listener
+	wg.Add(1)
 	go func() {
-		wg.Wait()
-		close(blockChan)
+		defer wg.Done()
+		metricsServer.Start(ctx)
 	}()
 
 	// Wait for context cancellation
 	<-ctx.Done()
 	logger.Info("Shutting down worker...")
+	wg.Wait()
 
 	return nil
 }
@@ -0,0 +1,373 @@
+package backfill
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/jmoiron/sqlx"
+	"golang.org/x/sync/errgroup"
+
+	"evm-tx-watcher/internal/blockchain/client"
+	"evm-tx-watcher/internal/config"
+	"evm-tx-watcher/internal/domain"
+	"evm-tx-watcher/internal/processor"
+	"evm-tx-watcher/internal/repository"
+	"evm-tx-watcher/internal/util"
+)
+
+// logAddressBatch bounds how many addresses go into one eth_getLogs topic filter
+const logAddressBatch = 100
+
+// transferSignatures are the token transfer events looked up with eth_getLogs
+var transferSignatures = []common.Hash{
+	client.ERC20TransferEventSignature,
+	client.ERC1155TransferSingleEventSignature,
+	client.ERC1155TransferBatchEventSignature,
+}
+
+// Options selects what a backfill covers
+type Options struct {
+	From        uint64
+	To          uint64   // must not exceed the network's confirmed height
+	Addresses   []string // empty means every address watched on the network
+	ChunkSize   uint64   // blocks per eth_getLogs range and per unit of progress
+	Concurrency int      // chunks processed in parallel
+	Notify      bool     // create webhook deliveries for stored transactions
+}
+
+// Backfiller imports the history of watched addresses over a block range.
+// Token transfers are found with eth_getLogs filtered on the address-padded
+// topics, native transfers by scanning every block's transactions. Progress is
+// recorded per chunk, so a run over the same range and addresses resumes.
+type Backfiller struct {
+	client     *client.Client
+	processor  *processor.Processor
+	unitOfWork repository.UnitOfWork
+	jobRepo    repository.BackfillJobRepository
+	network    config.NetworkConfig
+	logger     *util.Logger
+}
+
+func New(
+	c *client.Client,
+	proc *processor.Processor,
+	unitOfWork repository.UnitOfWork,
+	jobRepo repository.BackfillJobRepository,
+	logger *util.Logger,
+) *Backfiller {
+	return &Backfiller{
+		client:     c,
+		processor:  proc,
+		unitOfWork: unitOfWork,
+		jobRepo:    jobRepo,
+		network:    c.NetworkConfig,
+		logger:     logger,
+	}
+}
+
+// Run backfills the range described by opts until it is done or ctx is cancelled
+func (b *Backfiller) Run(ctx context.Context, opts Options) error {
+	chainID := b.network.ChainID
+
+	addresses := opts.Addresses
+	var only map[string]bool
+	if len(addresses) == 0 {
+		addresses = b.processor.WatchedAddresses(chainID)
+	} else {
+		// Transactions are only stored for watch entries, an unwatched address would match nothing
+		watched := make(map[string]bool)
+		for _, address := range b.processor.WatchedAddresses(chainID) {
+			watched[address] = true
+		}
+		only = make(map[string]bool, len(addresses))
+		var unwatched []string
+		for i, address := range addresses {
+			addresses[i] = strings.ToLower(address)
+			only[addresses[i]] = true
+			if !watched[addresses[i]] {
+				unwatched = append(unwatched, address)
+			}
+		}
+		if len(unwatched) > 0 {
+			return fmt.Errorf("not watched on %s: %s", b.network.Name, strings.Join(unwatched, ", "))
+		}
+	}
+	if len(addresses) == 0 {
+		return fmt.Errorf("no watched addresses on %s", b.network.Name)
+	}
+
+	confirmed, err := b.confirmedHeight(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get confirmed height: %w", err)
+	}
+	to := opts.To
+	if to > confirmed {
+		return fmt.Errorf("block %d is not confirmed yet, the highest confirmed block is %d", to, confirmed)
+	}
+	if opts.From > to {
+		return fmt.Errorf("from block %d is above to block %d", opts.From, to)
+	}
+
+	job, err := b.loadJob(ctx, opts.From, to, addresses, only == nil)
+	if err != nil {
+		return err
+	}
+	if job.CompletedAt != nil {
+		b.logger.Infof("[Backfill] %s: job %s already completed at %s", b.network.Name, job.ID, job.CompletedAt.Format(time.RFC3339))
+		return nil
+	}
+
+	start := uint64(job.NextBlock)
+	b.logger.Infof("[Backfill] %s: job %s blocks %d-%d (resuming at %d) addresses=%d notify=%t",
+		b.network.Name, job.ID, job.FromBlock, job.ToBlock, start, len(addresses), opts.Notify)
+
+	progress := &progress{job: job, done: make(map[uint64]chunk)}
+	topics := paddedTopics(addresses)
+	watched := make(map[common.Address]bool, len(addresses))
+	for _, address := range addresses {
+		watched[common.HexToAddress(address)] = true
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(opts.Concurrency, 1))
+	for from := start; from <= to; from += opts.ChunkSize {
+		c := chunk{from: from, to: min(from+opts.ChunkSize-1, to)}
+		g.Go(func() error {
+			if gctx.Err() != nil {
+				return gctx.Err()
+			}
+			stored, err := b.processChunk(gctx, c, topics, watched, only, opts.Notify)
+			if err != nil {
+				return fmt.Errorf("blocks %d-%d: %w", c.from, c.to, err)
+			}
+			c.stored = stored
+			return b.complete(gctx, progress, c)
+		})
+	}
+	if err := g.Wait(); err != nil {
+		if ctx.Err() != nil {
+			b.logger.Infof("[Backfill] %s: interrupted, resume from block %d", b.network.Name, progress.job.NextBlock)
+			return nil
+		}
+		return err
+	}
+
+	now := time.Now()
+	job.CompletedAt = &now
+	if err := b.saveJob(ctx, job); err != nil {
+		return err
+	}
+
+	b.logger.Infof("[Backfill] %s: job %s completed, stored %d transactions", b.network.Name, job.ID, job.StoredTransactions)
+	return nil
+}
+
+// chunk is a contiguous block range processed as one unit
+type chunk struct {
+	from, to uint64
+	stored   int
+}
+
+// progress tracks finished chunks so the job's next block only advances over
+// contiguous completed ranges
+type progress struct {
+	mu   sync.Mutex
+	job  *domain.BackfillJob
+	done map[uint64]chunk
+}
+
+// complete records a finished chunk and persists the advanced position
+func (b *Backfiller) complete(ctx context.Context, p *progress, c chunk) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.done[c.from] = c
+	advanced := false
+	for {
+		next, ok := p.done[uint64(p.job.NextBlock)]
+		if !ok {
+			break
+		}
+		delete(p.done, next.from)
+		p.job.NextBlock = int64(next.to) + 1
+		p.job.StoredTransactions += int64(next.stored)
+		advanced = true
+	}
+	if !advanced {
+		return nil
+	}
+
+	b.logger.Infof("[Backfill] %s: processed up to block %d of %d, stored %d transactions",
+		b.network.Name, p.job.NextBlock-1, p.job.ToBlock, p.job.StoredTransactions)
+	return b.saveJob(ctx, p.job)
+}
+
+// processChunk stores every transaction in the range that emitted a token
+// transfer involving, or was sent from or to, one of the watched addresses
+func (b *Backfiller) processChunk(ctx context.Context, c chunk, topics [][]common.Hash, watched map[common.Address]bool, only map[string]bool, notify bool) (int, error) {
+	logged, err := b.loggedTransactions(ctx, c, topics)
+	if err != nil {
+		return 0, err
+	}
+
+	stored := 0
+	for number := c.from; number <= c.to; number++ {
+		block, err := b.client.BlockByNumber(ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			return stored, fmt.Errorf("failed to get block %d: %w", number, err)
+		}
+
+		var indexes []int
+		for i, tx := range block.Transactions() {
+			if logged[tx.Hash()] || (tx.To() != nil && watched[*tx.To()]) {
+				indexes = append(indexes, i)
+				continue
+			}
+			from, err := b.client.Sender(tx)
+			if err != nil {
+				return stored, fmt.Errorf("failed to recover sender of tx %s in block %d: %w", tx.Hash().Hex(), number, err)
+			}
+			if watched[from] {
+				indexes = append(indexes, i)
+			}
+		}
+		if len(indexes) == 0 {
+			continue
+		}
+
+		details, err := b.client.GetTransactionDetails(ctx, block, indexes)
+		if err != nil {
+			return stored, fmt.Errorf("failed to get transactions of block %d: %w", number, err)
+		}
+		n, err := b.processor.StoreHistorical(ctx, b.network.ChainID, details, only, notify)
+		if err != nil {
+			return stored, err
+		}
+		stored += n
+	}
+	return stored, nil
+}
+
+// loggedTransactions returns the hashes of transactions in the range with a
+// token transfer log naming a watched address. ERC-20 and ERC-721 put the
+// parties in topics 1 and 2, ERC-1155 in topics 2 and 3, so all three
+// positions are queried.
+func (b *Backfiller) loggedTransactions(ctx context.Context, c chunk, topics [][]common.Hash) (map[common.Hash]bool, error) {
+	hashes := make(map[common.Hash]bool)
+	for _, batch := range topics {
+		for position := 1; position <= 3; position++ {
+			query := ethereum.FilterQuery{
+				FromBlock: new(big.Int).SetUint64(c.from),
+				ToBlock:   new(big.Int).SetUint64(c.to),
+				Topics:    make([][]common.Hash, position+1),
+			}
+			query.Topics[0] = transferSignatures
+			query.Topics[position] = batch
+
+			logs, err := b.client.FilterLogs(ctx, query)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get logs: %w", err)
+			}
+			for _, log := range logs {
+				if !log.Removed {
+					hashes[log.TxHash] = true
+				}
+			}
+		}
+	}
+	return hashes, nil
+}
+
+// confirmedHeight returns the highest block the network's confirmation policy
+// considers final; newer blocks are left to the worker
+func (b *Backfiller) confirmedHeight(ctx context.Context) (uint64, error) {
+	if b.network.ConfirmationPolicy == config.ConfirmationDepth {
+		head, err := b.client.GetLatestBlockNumber(ctx)
+		if err != nil {
+			return 0, err
+		}
+		depth := uint64(b.network.Confirmations)
+		if head < depth {
+			return 0, errors.New("chain is shorter than the confirmation depth")
+		}
+		return head - depth, nil
+	}
+
+	tag := rpc.SafeBlockNumber
+	if b.network.ConfirmationPolicy == config.ConfirmationFinalized {
+		tag = rpc.FinalizedBlockNumber
+	}
+	header, err := b.client.HeaderByNumber(ctx, big.NewInt(tag.Int64()))
+	if err != nil {
+		return 0, err
+	}
+	return header.Number.Uint64(), nil
+}
+
+// loadJob returns the stored job for the range and addresses, creating it on the first run
+func (b *Backfiller) loadJob(ctx context.Context, from, to uint64, addresses []string, all bool) (*domain.BackfillJob, error) {
+	id := jobID(b.network.ChainID, from, to, addresses, all)
+
+	job, err := b.jobRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job != nil {
+		return job, nil
+	}
+
+	job = &domain.BackfillJob{
+		ID:           id,
+		ChainID:      b.network.ChainID,
+		Network:      b.network.Name,
+		FromBlock:    int64(from),
+		ToBlock:      int64(to),
+		NextBlock:    int64(from),
+		AddressCount: len(addresses),
+	}
+	return job, b.saveJob(ctx, job)
+}
+
+func (b *Backfiller) saveJob(ctx context.Context, job *domain.BackfillJob) error {
+	return b.unitOfWork.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		return b.jobRepo.Upsert(ctx, tx, job)
+	})
+}
+
+// jobID identifies a backfill by chain, range and address selection, so
+// re-running the same command resumes the same job
+func jobID(chainID int64, from, to uint64, addresses []string, all bool) string {
+	scope := "all"
+	if !all {
+		sorted := append([]string(nil), addresses...)
+		sort.Strings(sorted)
+		sum := sha256.Sum256([]byte(strings.Join(sorted, ",")))
+		scope = hex.EncodeToString(sum[:8])
+	}
+	return fmt.Sprintf("%d:%d-%d:%s", chainID, from, to, scope)
+}
+
+// paddedTopics left-pads addresses to 32-byte topics in batches of logAddressBatch
+func paddedTopics(addresses []string) [][]common.Hash {
+	var batches [][]common.Hash
+	for start := 0; start < len(addresses); start += logAddressBatch {
+		end := min(start+logAddressBatch, len(addresses))
+		batch := make([]common.Hash, 0, end-start)
+		for _, address := range addresses[start:end] {
+			batch = append(batch, common.BytesToHash(common.HexToAddress(address).Bytes()))
+		}
+		batches = append(batches, batch)
+	}
+	return batches
+}
@@ -2,81 +2,271 @@ package client
 
 import (
 	"context"
+	"errors"
 	"evm-tx-watcher/internal/config"
 	"evm-tx-watcher/internal/domain"
+	"evm-tx-watcher/internal/metrics"
+	"evm-tx-watcher/internal/tracing"
 	"evm-tx-watcher/internal/util"
 	"fmt"
 	"math/big"
 	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// Client represents a blockchain client for a specific network
+// Client represents a blockchain client for a specific network. Calls are
+// routed to the healthiest of the network's RPC endpoints and fail over to the
+// next one when an endpoint errors.
 type Client struct {
 	NetworkConfig config.NetworkConfig
-	ethClient     *ethclient.Client
+	pool          *pool
 	logger        *util.Logger
+	cancel        context.CancelFunc
 }
 
 // ERC20TransferEvent represents the Transfer event signature
 var ERC20TransferEventSignature = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
 
-// TransactionDetails contains both transaction and its token transfers
+// TransactionDetails contains a transaction with its token and internal transfers
 type TransactionDetails struct {
-	Transaction    *domain.Transaction
-	TokenTransfers []domain.TokenTransfer
+	Transaction       *domain.Transaction
+	TokenTransfers    []domain.TokenTransfer
+	InternalTransfers []domain.InternalTransfer // only when internal tracing is enabled
 }
 
 // New creates a new blockchain client
 func New(networkConfig config.NetworkConfig, logger *util.Logger) (*Client, error) {
-	ethClient, err := ethclient.Dial(networkConfig.RPC)
-	if err != nil {
-		return nil, fmt.Errorf("failed to dial %s: %w", networkConfig.RPC, err)
-	}
-
 	client := &Client{
 		NetworkConfig: networkConfig,
-		ethClient:     ethClient,
+		pool:          newPool(networkConfig),
 		logger:        logger,
 	}
 
-	// Test connection and verify chain ID
+	// Test connections and verify chain ID on every endpoint
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	chainID, err := client.ethClient.ChainID(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get chain ID for %s: %w", networkConfig.Name, err)
+	connected := 0
+	for _, ep := range client.pool.endpoints {
+		if _, err := ep.client(ctx, networkConfig.ChainID); err != nil {
+			ep.recordFailure(err)
+			logger.WithError(err).Warnf("[%s] RPC endpoint %s unavailable", networkConfig.Name, redactURL(ep.url))
+			continue
+		}
+		connected++
 	}
 
-	if chainID.Int64() != networkConfig.ChainID {
-		return nil, fmt.Errorf("chain ID mismatch for %s: expected %d, got %d", 
-			networkConfig.Name, networkConfig.ChainID, chainID.Int64())
+	if connected == 0 {
+		client.pool.close()
+		return nil, fmt.Errorf("no usable RPC endpoint for %s", networkConfig.Name)
 	}
 
-	logger.Infof("Connected to %s (Chain ID: %d)", networkConfig.Name, chainID.Int64())
+	logger.Infof("Connected to %s (Chain ID: %d) via %d/%d endpoints",
+		networkConfig.Name, networkConfig.ChainID, connected, len(client.pool.endpoints))
+
+	monitorCtx, monitorCancel := context.WithCancel(context.Background())
+	client.cancel = monitorCancel
+	go client.monitor(monitorCtx)
+
 	return client, nil
 }
 
-// Close closes the client connection
+// Close closes the client connections
 func (c *Client) Close() {
-	c.ethClient.Close()
+	c.cancel()
+	c.pool.close()
+}
+
+// PreferredEndpoint returns the endpoint calls currently go to first, or an
+// empty string when every endpoint is disabled
+func (c *Client) PreferredEndpoint() string {
+	if endpoints := c.pool.ordered(false); len(endpoints) > 0 {
+		return redactURL(endpoints[0].url)
+	}
+	return ""
+}
+
+// EndpointHealth returns the health of every configured RPC endpoint
+func (c *Client) EndpointHealth() []EndpointHealth {
+	return c.pool.health()
+}
+
+// do runs fn against the endpoints in health order until one succeeds and
+// records every outcome, in the endpoint's health and in the metrics under
+// method. Not-found answers move on to the next endpoint without counting
+// against the one that gave them, since it may just lag.
+func (c *Client) do(ctx context.Context, method string, fn func(ep *endpoint, eth *ethclient.Client) error) error {
+	network := c.NetworkConfig.Name
+	var lastErr error
+	for _, ep := range c.pool.ordered(false) {
+		eth, err := ep.client(ctx, c.NetworkConfig.ChainID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			ep.recordFailure(err)
+			metrics.RPCRequests.WithLabelValues(network, method, "error").Inc()
+			lastErr = err
+			continue
+		}
+
+		started := time.Now()
+		err = fn(ep, eth)
+		elapsed := time.Since(started)
+		metrics.RPCDuration.WithLabelValues(network, method).Observe(elapsed.Seconds())
+		if err == nil {
+			ep.recordSuccess(elapsed)
+			metrics.RPCRequests.WithLabelValues(network, method, "success").Inc()
+			return nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+
+		if isExecutionReverted(err) {
+			// The node executed the call and it reverted, every endpoint would agree
+			ep.recordSuccess(elapsed)
+			metrics.RPCRequests.WithLabelValues(network, method, "success").Inc()
+			return err
+		}
+
+		lastErr = err
+		if errors.Is(err, ethereum.NotFound) {
+			ep.recordSuccess(elapsed)
+			metrics.RPCRequests.WithLabelValues(network, method, "not_found").Inc()
+			continue
+		}
+
+		ep.recordFailure(err)
+		metrics.RPCRequests.WithLabelValues(network, method, "error").Inc()
+		c.logger.WithError(err).Debugf("[%s] RPC call failed on %s, trying next endpoint",
+			c.NetworkConfig.Name, redactURL(ep.url))
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no RPC endpoints available for %s", c.NetworkConfig.Name)
+	}
+	return lastErr
+}
+
+// startCall starts a span for a single RPC call made on ep
+func (c *Client) startCall(ctx context.Context, ep *endpoint, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs,
+		attribute.String("rpc.system", "jsonrpc"),
+		attribute.String("rpc.method", method),
+		attribute.String("rpc.endpoint", redactURL(ep.url)),
+		attribute.String("network", c.NetworkConfig.Name),
+	)
+	return tracing.Start(ctx, method, attrs...)
+}
+
+// monitor periodically probes every endpoint so heads, latency and recovery
+// are tracked even for endpoints that are not currently receiving traffic
+func (c *Client) monitor(ctx context.Context) {
+	ticker := time.NewTicker(probeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, ep := range c.pool.endpoints {
+				c.probe(ctx, ep)
+			}
+		}
+	}
+}
+
+func (c *Client) probe(ctx context.Context, ep *endpoint) {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	eth, err := ep.client(ctx, c.NetworkConfig.ChainID)
+	if err != nil {
+		ep.recordFailure(err)
+		return
+	}
+
+	started := time.Now()
+	head, err := eth.BlockNumber(ctx)
+	if err != nil {
+		ep.recordFailure(err)
+		return
+	}
+	ep.recordSuccess(time.Since(started))
+	ep.recordHead(head)
 }
 
 // GetLatestBlockNumber returns the latest block number
 func (c *Client) GetLatestBlockNumber(ctx context.Context) (uint64, error) {
-	return c.ethClient.BlockNumber(ctx)
+	var head uint64
+	err := c.do(ctx, "eth_blockNumber", func(ep *endpoint, eth *ethclient.Client) error {
+		var err error
+		head, err = eth.BlockNumber(ctx)
+		if err == nil {
+			ep.recordHead(head)
+		}
+		return err
+	})
+	return head, err
+}
+
+// HeaderByHash returns the block header with the given hash
+func (c *Client) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	var header *types.Header
+	err := c.do(ctx, "eth_getBlockByHash", func(ep *endpoint, eth *ethclient.Client) error {
+		var err error
+		header, err = eth.HeaderByHash(ctx, hash)
+		return err
+	})
+	return header, err
 }
 
-// SubscribeNewHeads subscribes to new block headers
+// HeaderByNumber returns the block header at the given height, or the latest one when number is nil
+func (c *Client) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	var header *types.Header
+	err := c.do(ctx, "eth_getBlockByNumber", func(ep *endpoint, eth *ethclient.Client) error {
+		var err error
+		header, err = eth.HeaderByNumber(ctx, number)
+		return err
+	})
+	return header, err
+}
+
+// ErrExecutionReverted wraps eth_call failures raised by the EVM rather than the transport
+var ErrExecutionReverted = errors.New("execution reverted")
+
+// CallContract executes an eth_call at the given block, or the latest one when blockNumber is nil
+func (c *Client) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	var result []byte
+	err := c.do(ctx, "eth_call", func(ep *endpoint, eth *ethclient.Client) error {
+		var err error
+		result, err = eth.CallContract(ctx, msg, blockNumber)
+		return err
+	})
+	if err != nil && isExecutionReverted(err) {
+		return nil, fmt.Errorf("%w: %v", ErrExecutionReverted, err)
+	}
+	return result, err
+}
+
+// SubscribeNewHeads subscribes to new block headers on the healthiest
+// WebSocket endpoint. When the subscription breaks it is re-established on
+// another endpoint; the returned channel only reports an error once no
+// endpoint accepts the subscription.
 func (c *Client) SubscribeNewHeads(ctx context.Context, ch chan<- *types.Header) (chan error, error) {
-	sub, err := c.ethClient.SubscribeNewHead(ctx, ch)
+	heads := make(chan *types.Header, cap(ch))
+	sub, ep, err := c.subscribe(ctx, heads, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -84,56 +274,154 @@ func (c *Client) SubscribeNewHeads(ctx context.Context, ch chan<- *types.Header)
 	errCh := make(chan error, 1)
 	go func() {
 		defer close(errCh)
-		select {
-		case err := <-sub.Err():
-			errCh <- err
-		case <-ctx.Done():
-			return
+		for {
+			select {
+			case header := <-heads:
+				ep.recordHead(header.Number.Uint64())
+				select {
+				case ch <- header:
+				case <-ctx.Done():
+					sub.Unsubscribe()
+					ep.setSubscribed(false)
+					return
+				}
+
+			case err := <-sub.Err():
+				if err == nil {
+					err = fmt.Errorf("subscription closed by %s", redactURL(ep.url))
+				}
+				ep.setSubscribed(false)
+				ep.recordFailure(err)
+				c.logger.WithError(err).Warnf("[%s] Head subscription on %s failed, failing over",
+					c.NetworkConfig.Name, redactURL(ep.url))
+
+				sub, ep, err = c.subscribe(ctx, heads, ep)
+				if err != nil {
+					errCh <- err
+					return
+				}
+
+			case <-ctx.Done():
+				sub.Unsubscribe()
+				ep.setSubscribed(false)
+				return
+			}
 		}
 	}()
 
 	return errCh, nil
 }
 
-// GetBlockWithTransactions retrieves a block with all its transactions and receipts
-func (c *Client) GetBlockWithTransactions(ctx context.Context, blockNumber *big.Int) (*types.Block, []*TransactionDetails, error) {
-	block, err := c.ethClient.BlockByNumber(ctx, blockNumber)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to get block %d: %w", blockNumber.Int64(), err)
+// subscribe opens a head subscription on the healthiest WebSocket endpoint,
+// trying failed last only if nothing else works
+func (c *Client) subscribe(ctx context.Context, heads chan *types.Header, failed *endpoint) (ethereum.Subscription, *endpoint, error) {
+	candidates := c.pool.ordered(true)
+	if len(candidates) == 0 {
+		return nil, nil, fmt.Errorf("no WebSocket RPC endpoint configured for %s", c.NetworkConfig.Name)
+	}
+	for i, ep := range candidates {
+		if ep == failed && i < len(candidates)-1 {
+			candidates = append(append(candidates[:i:i], candidates[i+1:]...), ep)
+			break
+		}
 	}
 
-	var transactionDetails []*TransactionDetails
+	var lastErr error
+	for _, ep := range candidates {
+		eth, err := ep.client(ctx, c.NetworkConfig.ChainID)
+		if err == nil {
+			var sub ethereum.Subscription
+			if sub, err = eth.SubscribeNewHead(ctx, heads); err == nil {
+				ep.setSubscribed(true)
+				c.logger.Infof("[%s] Subscribed to new heads on %s", c.NetworkConfig.Name, redactURL(ep.url))
+				return sub, ep, nil
+			}
+		}
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+		ep.recordFailure(err)
+		lastErr = err
+	}
+	return nil, nil, lastErr
+}
 
-	for _, tx := range block.Transactions() {
-		// Get transaction receipt for logs and status
-		receipt, err := c.ethClient.TransactionReceipt(ctx, tx.Hash())
+// GetBlockWithTransactions retrieves a block with all its transactions and receipts.
+// The block and its receipts always come from the same endpoint. A transaction
+// that cannot be converted fails the whole block.
+func (c *Client) GetBlockWithTransactions(ctx context.Context, blockNumber *big.Int) (_ *types.Block, _ []*TransactionDetails, err error) {
+	ctx, span := tracing.Start(ctx, "client.GetBlockWithTransactions",
+		attribute.String("network", c.NetworkConfig.Name),
+		attribute.Int64("block.number", blockNumber.Int64()),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	var (
+		block    *types.Block
+		receipts []*types.Receipt
+		traces   [][]domain.InternalTransfer
+	)
+	err = c.do(ctx, "block_with_receipts", func(ep *endpoint, eth *ethclient.Client) error {
+		callCtx, callSpan := c.startCall(ctx, ep, "eth_getBlockByNumber")
+		var err error
+		block, err = eth.BlockByNumber(callCtx, blockNumber)
+		tracing.End(callSpan, err)
 		if err != nil {
-			c.logger.Warnf("[%s] Failed to get receipt for tx %s: %v", c.NetworkConfig.Name, tx.Hash().Hex(), err)
-			continue
+			return fmt.Errorf("failed to get block %d: %w", blockNumber.Int64(), err)
 		}
 
-		// Convert to domain transaction
-		domainTx, err := c.convertToDomainTransaction(block, tx, receipt)
+		// Get transaction receipts for logs and status; a missing one fails the block
+		receipts, err = c.fetchReceipts(ctx, ep, eth, block)
 		if err != nil {
-			c.logger.Warnf("[%s] Failed to convert transaction %s: %v", c.NetworkConfig.Name, tx.Hash().Hex(), err)
-			continue
+			return fmt.Errorf("failed to get receipts for block %d: %w", blockNumber.Int64(), err)
 		}
 
-		// Generate UUID for the transaction
-		domainTx.ID = uuid.New()
+		// Skipped only where tracing is unavailable, see traceBlock
+		traces, err = c.traceBlock(ctx, ep, eth, block)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	metrics.ReceiptsFetched.WithLabelValues(c.NetworkConfig.Name).Add(float64(len(receipts)))
+	span.SetAttributes(attribute.Int("block.transactions", len(receipts)))
 
-		// Extract token transfers from logs
-		tokenTransfers := c.extractTokenTransfers(domainTx.ID, receipt.Logs)
+	var transactionDetails []*TransactionDetails
 
-		transactionDetails = append(transactionDetails, &TransactionDetails{
-			Transaction:    domainTx,
-			TokenTransfers: tokenTransfers,
-		})
+	for i, tx := range block.Transactions() {
+		details, err := c.newTransactionDetails(block, tx, receipts[i])
+		if err != nil {
+			// Skipping would lose the transaction, the block is retried instead
+			return nil, nil, fmt.Errorf("failed to convert transaction %s of block %d: %w", tx.Hash().Hex(), blockNumber.Int64(), err)
+		}
+		if traces != nil {
+			details.InternalTransfers = attachInternalTransfers(details.Transaction.ID, traces[i])
+		}
+
+		transactionDetails = append(transactionDetails, details)
 	}
 
 	return block, transactionDetails, nil
 }
 
+// newTransactionDetails converts a transaction and its receipt and extracts
+// the token transfers from the receipt logs
+func (c *Client) newTransactionDetails(block *types.Block, tx *types.Transaction, receipt *types.Receipt) (*TransactionDetails, error) {
+	// Convert to domain transaction
+	domainTx, err := c.convertToDomainTransaction(block, tx, receipt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate UUID for the transaction
+	domainTx.ID = uuid.New()
+
+	return &TransactionDetails{
+		Transaction:    domainTx,
+		TokenTransfers: c.extractTokenTransfers(domainTx.ID, receipt.Logs),
+	}, nil
+}
+
 // convertToDomainTransaction converts go-ethereum types to domain transaction
 func (c *Client) convertToDomainTransaction(block *types.Block, tx *types.Transaction, receipt *types.Receipt) (*domain.Transaction, error) {
 	// Get the sender address using the correct signer for this chain
@@ -168,40 +456,3 @@ func (c *Client) convertToDomainTransaction(block *types.Block, tx *types.Transa
 
 	return domainTx, nil
 }
-
-// extractTokenTransfers extracts ERC-20 transfer events from transaction logs
-func (c *Client) extractTokenTransfers(transactionID uuid.UUID, logs []*types.Log) []domain.TokenTransfer {
-	var transfers []domain.TokenTransfer
-
-	for _, log := range logs {
-		// Check if this is an ERC-20 Transfer event
-		if len(log.Topics) != 3 || log.Topics[0] != ERC20TransferEventSignature {
-			continue
-		}
-
-		// Extract from and to addresses from topics
-		from := common.BytesToAddress(log.Topics[1].Bytes())
-		to := common.BytesToAddress(log.Topics[2].Bytes())
-
-		// Extract value from data (should be 32 bytes)
-		if len(log.Data) != 32 {
-			continue
-		}
-		value := new(big.Int).SetBytes(log.Data)
-
-		transfer := domain.TokenTransfer{
-			ID:            uuid.New(),
-			TransactionID: transactionID,
-			LogIndex:      int(log.Index),
-			TokenAddress:  strings.ToLower(log.Address.Hex()),
-			FromAddress:   strings.ToLower(from.Hex()),
-			ToAddress:     strings.ToLower(to.Hex()),
-			Value:         value,
-			CreatedAt:     time.Now(),
-		}
-
-		transfers = append(transfers, transfer)
-	}
-
-	return transfers
-}
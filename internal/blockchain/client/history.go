@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"evm-tx-watcher/internal/domain"
+	"evm-tx-watcher/internal/metrics"
+	"evm-tx-watcher/internal/tracing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/errgroup"
+)
+
+// FilterLogs returns the logs matching query
+func (c *Client) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	var logs []types.Log
+	err := c.do(ctx, "eth_getLogs", func(ep *endpoint, eth *ethclient.Client) error {
+		var err error
+		logs, err = eth.FilterLogs(ctx, query)
+		return err
+	})
+	return logs, err
+}
+
+// BlockByNumber returns the block at the given height with its transactions but without receipts
+func (c *Client) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	var block *types.Block
+	err := c.do(ctx, "eth_getBlockByNumber", func(ep *endpoint, eth *ethclient.Client) error {
+		var err error
+		block, err = eth.BlockByNumber(ctx, number)
+		return err
+	})
+	return block, err
+}
+
+// Sender recovers the address that signed tx
+func (c *Client) Sender(tx *types.Transaction) (common.Address, error) {
+	return types.Sender(types.LatestSignerForChainID(big.NewInt(c.NetworkConfig.ChainID)), tx)
+}
+
+// GetTransactionDetails fetches the receipts of the transactions of block at
+// the given indexes only, which is cheaper than GetBlockWithTransactions when
+// most of the block is irrelevant. Internal transfers are traced for the whole
+// block when the network enables it. A transaction that cannot be converted
+// fails the call.
+func (c *Client) GetTransactionDetails(ctx context.Context, block *types.Block, indexes []int) ([]*TransactionDetails, error) {
+	txs := block.Transactions()
+	receipts := make([]*types.Receipt, len(indexes))
+	var traces [][]domain.InternalTransfer
+
+	err := c.do(ctx, "transaction_receipts", func(ep *endpoint, eth *ethclient.Client) error {
+		g, gctx := errgroup.WithContext(ctx)
+		g.SetLimit(receiptConcurrency)
+		for i, index := range indexes {
+			i, hash := i, txs[index].Hash()
+			g.Go(func() error {
+				callCtx, span := c.startCall(gctx, ep, "eth_getTransactionReceipt", attribute.String("tx.hash", hash.Hex()))
+				receipt, err := eth.TransactionReceipt(callCtx, hash)
+				tracing.End(span, err)
+				if err != nil {
+					return fmt.Errorf("failed to get receipt for tx %s: %w", hash.Hex(), err)
+				}
+				if receipt.BlockHash != block.Hash() {
+					return fmt.Errorf("receipt for tx %s belongs to block %s", hash.Hex(), receipt.BlockHash.Hex())
+				}
+				receipts[i] = receipt
+				return nil
+			})
+		}
+		if err := g.Wait(); err != nil {
+			return err
+		}
+
+		// Skipped only where tracing is unavailable, see traceBlock
+		var err error
+		traces, err = c.traceBlock(ctx, ep, eth, block)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	metrics.ReceiptsFetched.WithLabelValues(c.NetworkConfig.Name).Add(float64(len(receipts)))
+
+	details := make([]*TransactionDetails, 0, len(indexes))
+	for i, index := range indexes {
+		d, err := c.newTransactionDetails(block, txs[index], receipts[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert transaction %s: %w", txs[index].Hash().Hex(), err)
+		}
+		if traces != nil {
+			d.InternalTransfers = attachInternalTransfers(d.Transaction.ID, traces[index])
+		}
+		details = append(details, d)
+	}
+	return details, nil
+}
@@ -0,0 +1,287 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"evm-tx-watcher/internal/config"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const (
+	// healthAlpha is the weight of the newest sample in the moving averages
+	healthAlpha = 0.2
+	// failureThreshold consecutive failures put an endpoint into cooldown
+	failureThreshold = 3
+	// cooldownPeriod is how long a failing endpoint is only used as a last resort
+	cooldownPeriod = 30 * time.Second
+	// probeInterval is how often every endpoint's head and latency are sampled
+	probeInterval = 15 * time.Second
+	// probeTimeout bounds a single health probe
+	probeTimeout = 5 * time.Second
+)
+
+// EndpointHealth is an operator facing snapshot of one RPC endpoint
+type EndpointHealth struct {
+	URL                 string    `json:"url"` // scheme and host only, paths often carry API keys
+	WebSocket           bool      `json:"websocket"`
+	Healthy             bool      `json:"healthy"`
+	Disabled            bool      `json:"disabled"`
+	LatencyMs           float64   `json:"latency_ms"`
+	ErrorRate           float64   `json:"error_rate"`
+	Head                uint64    `json:"head"`
+	HeadLag             uint64    `json:"head_lag"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	LastError           string    `json:"last_error,omitempty"`
+	LastErrorAt         time.Time `json:"last_error_at,omitempty"`
+	Subscribed          bool      `json:"subscribed"`
+}
+
+// endpoint is one RPC URL of a network together with its health statistics
+type endpoint struct {
+	url       string
+	index     int // position in the configured order, used as tie breaker
+	websocket bool
+
+	// Capabilities discovered at runtime, see fetchReceipts and traceBlock
+	blockReceiptsUnsupported atomic.Bool
+	batchUnsupported         atomic.Bool
+	traceUnsupported         atomic.Bool
+
+	mu                  sync.Mutex
+	eth                 *ethclient.Client
+	disabled            error // set when the endpoint serves the wrong chain
+	latency             float64
+	errorRate           float64
+	head                uint64
+	consecutiveFailures int
+	cooldownUntil       time.Time
+	lastError           string
+	lastErrorAt         time.Time
+	subscribed          bool
+}
+
+// client returns the endpoint's connection, dialing and verifying the chain ID on first use
+func (e *endpoint) client(ctx context.Context, chainID int64) (*ethclient.Client, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.disabled != nil {
+		return nil, e.disabled
+	}
+	if e.eth != nil {
+		return e.eth, nil
+	}
+
+	eth, err := ethclient.DialContext(ctx, e.url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s: %w", redactURL(e.url), err)
+	}
+
+	remoteChainID, err := eth.ChainID(ctx)
+	if err != nil {
+		eth.Close()
+		return nil, fmt.Errorf("failed to get chain ID from %s: %w", redactURL(e.url), err)
+	}
+	if remoteChainID.Int64() != chainID {
+		eth.Close()
+		e.disabled = fmt.Errorf("chain ID mismatch on %s: expected %d, got %d",
+			redactURL(e.url), chainID, remoteChainID.Int64())
+		return nil, e.disabled
+	}
+
+	e.eth = eth
+	return eth, nil
+}
+
+func (e *endpoint) recordSuccess(latency time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	ms := float64(latency.Microseconds()) / 1000
+	if e.latency == 0 {
+		e.latency = ms
+	} else {
+		e.latency = healthAlpha*ms + (1-healthAlpha)*e.latency
+	}
+	e.errorRate = (1 - healthAlpha) * e.errorRate
+	e.consecutiveFailures = 0
+	e.cooldownUntil = time.Time{}
+}
+
+func (e *endpoint) recordFailure(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.errorRate = healthAlpha + (1-healthAlpha)*e.errorRate
+	e.consecutiveFailures++
+	e.lastError = err.Error()
+	e.lastErrorAt = time.Now()
+	if e.consecutiveFailures >= failureThreshold {
+		e.cooldownUntil = time.Now().Add(cooldownPeriod)
+	}
+}
+
+func (e *endpoint) recordHead(head uint64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if head > e.head {
+		e.head = head
+	}
+}
+
+func (e *endpoint) setSubscribed(subscribed bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.subscribed = subscribed
+}
+
+// score ranks endpoints, lower is better: latency in milliseconds plus
+// penalties for recent errors and for lagging behind the best known head
+func (e *endpoint) score(bestHead uint64) float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var lag uint64
+	if e.head > 0 && bestHead > e.head {
+		lag = bestHead - e.head
+	}
+	return e.latency + e.errorRate*1000 + float64(lag)*250 + float64(e.index)*5
+}
+
+func (e *endpoint) usable() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.disabled == nil && time.Now().After(e.cooldownUntil)
+}
+
+func (e *endpoint) close() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.eth != nil {
+		e.eth.Close()
+		e.eth = nil
+	}
+}
+
+// pool holds every endpoint of a network and orders them by health
+type pool struct {
+	endpoints []*endpoint
+}
+
+func newPool(network config.NetworkConfig) *pool {
+	p := &pool{}
+	for i, rawURL := range network.RPCURLs() {
+		p.endpoints = append(p.endpoints, &endpoint{
+			url:       rawURL,
+			index:     i,
+			websocket: config.IsWebSocketURL(rawURL),
+		})
+	}
+	return p
+}
+
+// bestHead returns the highest head reported by any endpoint
+func (p *pool) bestHead() uint64 {
+	var best uint64
+	for _, ep := range p.endpoints {
+		ep.mu.Lock()
+		if ep.head > best {
+			best = ep.head
+		}
+		ep.mu.Unlock()
+	}
+	return best
+}
+
+// ordered returns the endpoints healthiest first; endpoints in cooldown are
+// kept at the end as a last resort and disabled ones are left out
+func (p *pool) ordered(websocketOnly bool) []*endpoint {
+	bestHead := p.bestHead()
+
+	type ranked struct {
+		ep     *endpoint
+		usable bool
+		score  float64
+	}
+	var candidates []ranked
+	for _, ep := range p.endpoints {
+		if websocketOnly && !ep.websocket {
+			continue
+		}
+		ep.mu.Lock()
+		disabled := ep.disabled != nil
+		ep.mu.Unlock()
+		if disabled {
+			continue
+		}
+		candidates = append(candidates, ranked{ep: ep, usable: ep.usable(), score: ep.score(bestHead)})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].usable != candidates[j].usable {
+			return candidates[i].usable
+		}
+		return candidates[i].score < candidates[j].score
+	})
+
+	endpoints := make([]*endpoint, 0, len(candidates))
+	for _, c := range candidates {
+		endpoints = append(endpoints, c.ep)
+	}
+	return endpoints
+}
+
+// health returns a snapshot of every endpoint in configured order
+func (p *pool) health() []EndpointHealth {
+	bestHead := p.bestHead()
+
+	snapshot := make([]EndpointHealth, 0, len(p.endpoints))
+	for _, ep := range p.endpoints {
+		usable := ep.usable()
+
+		ep.mu.Lock()
+		h := EndpointHealth{
+			URL:                 redactURL(ep.url),
+			WebSocket:           ep.websocket,
+			Healthy:             usable,
+			Disabled:            ep.disabled != nil,
+			LatencyMs:           ep.latency,
+			ErrorRate:           ep.errorRate,
+			Head:                ep.head,
+			ConsecutiveFailures: ep.consecutiveFailures,
+			LastError:           ep.lastError,
+			LastErrorAt:         ep.lastErrorAt,
+			Subscribed:          ep.subscribed,
+		}
+		if ep.head > 0 && bestHead > ep.head {
+			h.HeadLag = bestHead - ep.head
+		}
+		ep.mu.Unlock()
+
+		snapshot = append(snapshot, h)
+	}
+	return snapshot
+}
+
+func (p *pool) close() {
+	for _, ep := range p.endpoints {
+		ep.close()
+	}
+}
+
+// redactURL strips everything but scheme and host, since RPC paths and
+// query strings usually embed provider API keys
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "<invalid url>"
+	}
+	return u.Scheme + "://" + u.Host
+}
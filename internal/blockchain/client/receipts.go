@@ -0,0 +1,167 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"evm-tx-watcher/internal/tracing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// receiptBatchSize bounds how many receipts are requested per JSON-RPC batch
+	receiptBatchSize = 100
+	// receiptConcurrency bounds parallel eth_getTransactionReceipt calls
+	receiptConcurrency = 8
+	// methodNotFoundCode is the JSON-RPC error code for unsupported methods
+	methodNotFoundCode = -32601
+	// executionRevertedCode is the JSON-RPC error code geth uses for reverted calls
+	executionRevertedCode = 3
+)
+
+// fetchReceipts returns the receipts of every transaction in block, in
+// transaction order. It prefers a single eth_getBlockReceipts call, falls back
+// to batched eth_getTransactionReceipt requests and finally to individual calls.
+// Any receipt that cannot be fetched fails the whole block so it can be retried.
+func (c *Client) fetchReceipts(ctx context.Context, ep *endpoint, eth *ethclient.Client, block *types.Block) ([]*types.Receipt, error) {
+	txs := block.Transactions()
+	if len(txs) == 0 {
+		return nil, nil
+	}
+
+	if !ep.blockReceiptsUnsupported.Load() {
+		callCtx, span := c.startCall(ctx, ep, "eth_getBlockReceipts")
+		receipts, err := eth.BlockReceipts(callCtx, rpc.BlockNumberOrHashWithHash(block.Hash(), false))
+		tracing.End(span, err)
+		if err == nil {
+			if err = matchReceipts(txs, receipts); err == nil {
+				return receipts, nil
+			}
+		}
+		if isMethodNotFound(err) {
+			ep.blockReceiptsUnsupported.Store(true)
+			c.logger.Infof("[%s] eth_getBlockReceipts not supported by %s, using batched receipt requests",
+				c.NetworkConfig.Name, redactURL(ep.url))
+		} else {
+			c.logger.WithError(err).Warnf("[%s] eth_getBlockReceipts failed for block %d, falling back",
+				c.NetworkConfig.Name, block.NumberU64())
+		}
+	}
+
+	receipts := make([]*types.Receipt, len(txs))
+
+	if !ep.batchUnsupported.Load() {
+		if err := c.batchReceipts(ctx, ep, eth, txs, receipts); err != nil {
+			if isMethodNotFound(err) {
+				ep.batchUnsupported.Store(true)
+			}
+			c.logger.WithError(err).Warnf("[%s] Batched receipt request failed for block %d, fetching individually",
+				c.NetworkConfig.Name, block.NumberU64())
+		}
+	}
+
+	// Fetch whatever the batches did not return, one call per transaction
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(receiptConcurrency)
+	for i, tx := range txs {
+		if receipts[i] != nil {
+			continue
+		}
+		i, hash := i, tx.Hash()
+		g.Go(func() error {
+			callCtx, span := c.startCall(gctx, ep, "eth_getTransactionReceipt", attribute.String("tx.hash", hash.Hex()))
+			receipt, err := eth.TransactionReceipt(callCtx, hash)
+			tracing.End(span, err)
+			if err != nil {
+				return fmt.Errorf("failed to get receipt for tx %s: %w", hash.Hex(), err)
+			}
+			receipts[i] = receipt
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	if err := matchReceipts(txs, receipts); err != nil {
+		return nil, err
+	}
+	return receipts, nil
+}
+
+// batchReceipts requests receipts in JSON-RPC batches of bounded size. Entries
+// that come back empty or with an error are left nil for the caller to retry.
+func (c *Client) batchReceipts(ctx context.Context, ep *endpoint, eth *ethclient.Client, txs types.Transactions, receipts []*types.Receipt) error {
+	for start := 0; start < len(txs); start += receiptBatchSize {
+		end := min(start+receiptBatchSize, len(txs))
+
+		batch := make([]rpc.BatchElem, 0, end-start)
+		for i := start; i < end; i++ {
+			batch = append(batch, rpc.BatchElem{
+				Method: "eth_getTransactionReceipt",
+				Args:   []interface{}{txs[i].Hash()},
+				Result: &receipts[i],
+			})
+		}
+
+		callCtx, span := c.startCall(ctx, ep, "eth_getTransactionReceipt", attribute.Int("rpc.batch_size", len(batch)))
+		err := eth.Client().BatchCallContext(callCtx, batch)
+		tracing.End(span, err)
+		if err != nil {
+			return err
+		}
+
+		for i, elem := range batch {
+			if elem.Error != nil {
+				receipts[start+i] = nil
+			}
+		}
+	}
+	return nil
+}
+
+// matchReceipts checks that receipts line up one to one with txs
+func matchReceipts(txs types.Transactions, receipts []*types.Receipt) error {
+	if len(receipts) != len(txs) {
+		return fmt.Errorf("got %d receipts for %d transactions", len(receipts), len(txs))
+	}
+	for i, tx := range txs {
+		if receipts[i] == nil {
+			return fmt.Errorf("missing receipt for tx %s", tx.Hash().Hex())
+		}
+		if receipts[i].TxHash != (common.Hash{}) && receipts[i].TxHash != tx.Hash() {
+			return fmt.Errorf("receipt %d belongs to tx %s, expected %s", i, receipts[i].TxHash.Hex(), tx.Hash().Hex())
+		}
+	}
+	return nil
+}
+
+// isMethodNotFound reports whether the node rejected the call as unsupported
+func isMethodNotFound(err error) bool {
+	var rpcErr rpc.Error
+	return errors.As(err, &rpcErr) && rpcErr.ErrorCode() == methodNotFoundCode
+}
+
+// isExecutionReverted reports whether a call was executed by the node and
+// failed inside the EVM, such as a revert or an invalid opcode
+func isExecutionReverted(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == executionRevertedCode {
+		return true
+	}
+	message := strings.ToLower(err.Error())
+	for _, vmError := range []string{"execution reverted", "invalid opcode", "out of gas", "stack underflow"} {
+		if strings.Contains(message, vmError) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"evm-tx-watcher/internal/domain"
+	"evm-tx-watcher/internal/tracing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/google/uuid"
+)
+
+// traceTimeout is handed to the node; tracing a full block is expensive
+const traceTimeout = "30s"
+
+// callFrame is one call of a callTracer result
+type callFrame struct {
+	Type  string       `json:"type"`
+	From  string       `json:"from"`
+	To    string       `json:"to"`
+	Value *hexutil.Big `json:"value"`
+	Error string       `json:"error"`
+	Calls []callFrame  `json:"calls"`
+}
+
+// txTrace is one entry of a debug_traceBlockByNumber response
+type txTrace struct {
+	TxHash common.Hash `json:"txHash"`
+	Result *callFrame  `json:"result"`
+	Error  string      `json:"error"`
+}
+
+// traceBlock returns the value moving internal calls of every transaction in
+// block, indexed like block.Transactions(). It returns nil without an error
+// when the endpoint has no debug namespace, so the block is processed without
+// them. Any other failure is returned, so the block is retried or served by
+// another endpoint instead of losing its internal transfers.
+func (c *Client) traceBlock(ctx context.Context, ep *endpoint, eth *ethclient.Client, block *types.Block) ([][]domain.InternalTransfer, error) {
+	if !c.NetworkConfig.TraceInternal || ep.traceUnsupported.Load() || len(block.Transactions()) == 0 {
+		return nil, nil
+	}
+
+	callCtx, span := c.startCall(ctx, ep, "debug_traceBlockByNumber")
+	var traces []txTrace
+	err := eth.Client().CallContext(callCtx, &traces, "debug_traceBlockByNumber",
+		hexutil.EncodeBig(block.Number()),
+		map[string]interface{}{"tracer": "callTracer", "timeout": traceTimeout},
+	)
+	tracing.End(span, err)
+	if err != nil {
+		if isMethodNotFound(err) || strings.Contains(err.Error(), "does not exist") {
+			ep.traceUnsupported.Store(true)
+			c.logger.Warnf("[%s] debug_traceBlockByNumber not available on %s, internal transfers are skipped there",
+				c.NetworkConfig.Name, redactURL(ep.url))
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to trace block %d: %w", block.NumberU64(), err)
+	}
+
+	txs := block.Transactions()
+	if len(traces) != len(txs) {
+		return nil, fmt.Errorf("got %d traces for %d transactions in block %d", len(traces), len(txs), block.NumberU64())
+	}
+
+	transfers := make([][]domain.InternalTransfer, len(txs))
+	for i, trace := range traces {
+		if trace.TxHash != (common.Hash{}) && trace.TxHash != txs[i].Hash() {
+			return nil, fmt.Errorf("trace %d of block %d belongs to another transaction", i, block.NumberU64())
+		}
+		if trace.Error != "" {
+			return nil, fmt.Errorf("failed to trace tx %s: %s", txs[i].Hash().Hex(), trace.Error)
+		}
+		if trace.Result == nil || trace.Result.Error != "" {
+			continue // failed transactions move no value
+		}
+		for _, call := range trace.Result.Calls {
+			transfers[i] = collectValueCalls(call, 1, transfers[i])
+		}
+	}
+	return transfers, nil
+}
+
+// collectValueCalls walks a call tree and appends every call that moved native
+// value. Reverted calls are skipped together with their subcalls, and
+// DELEGATECALL frames only repeat the caller's value.
+func collectValueCalls(frame callFrame, depth int, transfers []domain.InternalTransfer) []domain.InternalTransfer {
+	if frame.Error != "" {
+		return transfers
+	}
+
+	callType := strings.ToUpper(frame.Type)
+	if frame.Value != nil && frame.Value.ToInt().Sign() > 0 && callType != "DELEGATECALL" && callType != "STATICCALL" {
+		transfers = append(transfers, domain.InternalTransfer{
+			ID:          uuid.New(),
+			TraceIndex:  len(transfers),
+			CallType:    callType,
+			FromAddress: strings.ToLower(frame.From),
+			ToAddress:   strings.ToLower(frame.To),
+			Value:       new(big.Int).Set(frame.Value.ToInt()),
+			Depth:       depth,
+			CreatedAt:   time.Now(),
+		})
+	}
+
+	for _, call := range frame.Calls {
+		transfers = collectValueCalls(call, depth+1, transfers)
+	}
+	return transfers
+}
+
+// attachInternalTransfers links traced transfers to their transaction
+func attachInternalTransfers(transactionID uuid.UUID, transfers []domain.InternalTransfer) []domain.InternalTransfer {
+	for i := range transfers {
+		transfers[i].TransactionID = transactionID
+	}
+	return transfers
+}
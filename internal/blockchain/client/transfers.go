@@ -0,0 +1,126 @@
+package client
+
+import (
+	"math/big"
+	"strings"
+	"time"
+
+	"evm-tx-watcher/internal/domain"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+)
+
+var (
+	// ERC1155TransferSingleEventSignature is TransferSingle(operator, from, to, id, value)
+	ERC1155TransferSingleEventSignature = crypto.Keccak256Hash([]byte("TransferSingle(address,address,address,uint256,uint256)"))
+	// ERC1155TransferBatchEventSignature is TransferBatch(operator, from, to, ids, values)
+	ERC1155TransferBatchEventSignature = crypto.Keccak256Hash([]byte("TransferBatch(address,address,address,uint256[],uint256[])"))
+
+	// transferBatchData decodes the non-indexed ids and values of a TransferBatch log
+	transferBatchData = func() abi.Arguments {
+		uint256Array, _ := abi.NewType("uint256[]", "", nil)
+		return abi.Arguments{{Type: uint256Array}, {Type: uint256Array}}
+	}()
+)
+
+// extractTokenTransfers extracts ERC-20, ERC-721 and ERC-1155 transfer events from transaction logs.
+// ERC-20 and ERC-721 share the Transfer signature and differ in whether the
+// third argument is indexed: ERC-721 logs carry the token ID as a fourth topic.
+func (c *Client) extractTokenTransfers(transactionID uuid.UUID, logs []*types.Log) []domain.TokenTransfer {
+	var transfers []domain.TokenTransfer
+
+	for _, log := range logs {
+		if len(log.Topics) == 0 {
+			continue
+		}
+
+		base := domain.TokenTransfer{
+			TransactionID: transactionID,
+			LogIndex:      int(log.Index),
+			TokenAddress:  strings.ToLower(log.Address.Hex()),
+			CreatedAt:     time.Now(),
+		}
+
+		switch log.Topics[0] {
+		case ERC20TransferEventSignature:
+			switch {
+			case len(log.Topics) == 3 && len(log.Data) == 32:
+				// ERC-20: value in data
+				transfer := base
+				transfer.Standard = domain.TokenStandardERC20
+				transfer.FromAddress = topicAddress(log.Topics[1])
+				transfer.ToAddress = topicAddress(log.Topics[2])
+				transfer.Value = new(big.Int).SetBytes(log.Data)
+				transfers = append(transfers, withID(transfer))
+
+			case len(log.Topics) == 4 && len(log.Data) == 0:
+				// ERC-721: token ID indexed, always a single token
+				transfer := base
+				transfer.Standard = domain.TokenStandardERC721
+				transfer.FromAddress = topicAddress(log.Topics[1])
+				transfer.ToAddress = topicAddress(log.Topics[2])
+				transfer.TokenID = log.Topics[3].Big()
+				transfer.Value = big.NewInt(1)
+				transfers = append(transfers, withID(transfer))
+			}
+
+		case ERC1155TransferSingleEventSignature:
+			if len(log.Topics) != 4 || len(log.Data) != 64 {
+				continue
+			}
+			transfer := erc1155Transfer(base, log)
+			transfer.TokenID = new(big.Int).SetBytes(log.Data[:32])
+			transfer.Value = new(big.Int).SetBytes(log.Data[32:])
+			transfers = append(transfers, withID(transfer))
+
+		case ERC1155TransferBatchEventSignature:
+			if len(log.Topics) != 4 {
+				continue
+			}
+			values, err := transferBatchData.Unpack(log.Data)
+			if err != nil {
+				c.logger.WithError(err).Warnf("[%s] Skipping malformed TransferBatch log %d in tx %s",
+					c.NetworkConfig.Name, log.Index, log.TxHash.Hex())
+				continue
+			}
+			ids, _ := values[0].([]*big.Int)
+			amounts, _ := values[1].([]*big.Int)
+			if len(ids) != len(amounts) {
+				continue
+			}
+			for i := range ids {
+				transfer := erc1155Transfer(base, log)
+				transfer.BatchIndex = i
+				transfer.TokenID = ids[i]
+				transfer.Value = amounts[i]
+				transfers = append(transfers, withID(transfer))
+			}
+		}
+	}
+
+	return transfers
+}
+
+// erc1155Transfer fills the indexed operator, from and to of an ERC-1155 log
+func erc1155Transfer(base domain.TokenTransfer, log *types.Log) domain.TokenTransfer {
+	operator := topicAddress(log.Topics[1])
+	base.Standard = domain.TokenStandardERC1155
+	base.OperatorAddress = &operator
+	base.FromAddress = topicAddress(log.Topics[2])
+	base.ToAddress = topicAddress(log.Topics[3])
+	return base
+}
+
+func withID(transfer domain.TokenTransfer) domain.TokenTransfer {
+	transfer.ID = uuid.New()
+	return transfer
+}
+
+// topicAddress decodes an indexed address argument
+func topicAddress(topic common.Hash) string {
+	return strings.ToLower(common.BytesToAddress(topic.Bytes()).Hex())
+}
@@ -3,126 +3,616 @@ package watcher
 import (
 	"context"
 	"fmt"
+	"math/big"
+	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 
 	"evm-tx-watcher/internal/blockchain/client"
 	"evm-tx-watcher/internal/config"
+	"evm-tx-watcher/internal/metrics"
+	"evm-tx-watcher/internal/repository"
+	"evm-tx-watcher/internal/tracing"
 	"evm-tx-watcher/internal/util"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// reorgWindow is the number of recent canonical headers kept for reorg detection
+	reorgWindow = 128
+	// subscriptionFailureLimit consecutive subscription failures switch the watcher to polling
+	subscriptionFailureLimit = 3
+	// restartDelay is the pause before a failed head source is restarted
+	restartDelay = 10 * time.Second
+)
+
+// BlockEventType describes what happened to a block
+type BlockEventType string
+
+const (
+	// BlockEventIncluded is emitted for new canonical blocks when inclusion notifications are enabled
+	BlockEventIncluded BlockEventType = "included"
+	// BlockEventConfirmed is emitted once a block satisfies the network's confirmation policy
+	BlockEventConfirmed BlockEventType = "confirmed"
+	// BlockEventReorged is emitted when a previously confirmed block left the canonical chain
+	BlockEventReorged BlockEventType = "reorged"
 )
 
-// BlockEvent represents a confirmed block with its transactions
+// BlockEvent represents an included or confirmed block with its transactions, or a block that was reorged out
 type BlockEvent struct {
+	Type               BlockEventType
 	NetworkConfig      config.NetworkConfig
-	Block              *types.Block
+	Block              *types.Block  // set for included and confirmed blocks
+	Header             *types.Header // header of the affected block, set for every event
 	TransactionDetails []*client.TransactionDetails
+	Trace              trace.SpanContext // span of the block's fetch, parent of its processing
 }
 
 type Watcher struct {
 	client        *client.Client
-	confirmations int64
+	cursorRepo    repository.BlockCursorRepository
+	maxCatchUp    int64
 	logger        *util.Logger
 	networkConfig config.NetworkConfig
+	source        HeadSource
+
+	chain           *canonicalChain
+	head            uint64      // highest block number seen on the chain
+	lastEmitted     uint64      // highest block number sent downstream as confirmed
+	lastEmittedHash common.Hash // hash of that block, zero when unknown
+	lastIncluded    uint64      // highest block number sent downstream as included
+
+	finalized         uint64    // highest canonical block at or below the safe/finalized tag
+	finalityCheckedAt time.Time // when the tag was last polled
+
+	mu     sync.RWMutex
+	status Status // copy of the progress above for readers outside the watcher goroutine
+}
+
+// Status is a snapshot of a watcher's progress for operators
+type Status struct {
+	HeadSource  string    `json:"head_source"`
+	Head        uint64    `json:"head"`         // highest block number seen on the chain
+	HeadSeenAt  time.Time `json:"head_seen_at"` // when that block was first seen
+	Confirmed   uint64    `json:"confirmed"`    // highest block released to the processor as confirmed
+	ConfirmedAt time.Time `json:"confirmed_at"`
 }
 
-func New(c *client.Client, networkConfig config.NetworkConfig, confirmations int64, logger *util.Logger) *Watcher {
+func New(
+	c *client.Client,
+	cursorRepo repository.BlockCursorRepository,
+	networkConfig config.NetworkConfig,
+	maxCatchUp int64,
+	logger *util.Logger,
+) *Watcher {
 	return &Watcher{
 		client:        c,
-		confirmations: confirmations,
+		cursorRepo:    cursorRepo,
+		maxCatchUp:    maxCatchUp,
 		logger:        logger,
 		networkConfig: networkConfig,
+		source:        newHeadSource(c, networkConfig, logger),
+		chain:         newCanonicalChain(reorgWindow),
 	}
 }
 
-func (w *Watcher) Start(ctx context.Context, out chan<- *types.Block) error {
+func (w *Watcher) Start(ctx context.Context, out *BlockQueue) error {
 	// Get latest block number for initial sync
 	latestBlock, err := w.client.GetLatestBlockNumber(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get latest block number for %s: %w", w.networkConfig.Name, err)
 	}
 
-	w.logger.Infof("[%s] Starting watcher, current head=%d, confirmations=%d",
-		w.networkConfig.Name, latestBlock, w.confirmations)
+	w.logger.Infof("[%s] Starting watcher, current head=%d, confirmation policy=%s, head source=%s",
+		w.networkConfig.Name, latestBlock, w.policyName(), w.source.Name())
+	w.observeHead(latestBlock)
+
+	// Replay everything mined since the last processed block before going live
+	if err := w.catchUp(ctx, latestBlock, out); err != nil {
+		return fmt.Errorf("failed to catch up %s: %w", w.networkConfig.Name, err)
+	}
 
-	// Subscribe to new headers
 	headers := make(chan *types.Header, 10)
-	errCh, err := w.client.SubscribeNewHeads(ctx, headers)
-	if err != nil {
-		return fmt.Errorf("failed to subscribe to new heads for %s: %w", w.networkConfig.Name, err)
+	failures := 0
+	for {
+		received, err := w.follow(ctx, headers, out)
+		if ctx.Err() != nil {
+			w.logger.Infof("[%s] Watcher stopped", w.networkConfig.Name)
+			return nil
+		}
+
+		if received > 0 {
+			failures = 0
+		}
+		failures++
+
+		if _, ok := w.source.(*subscriptionSource); ok && failures >= subscriptionFailureLimit {
+			w.logger.WithError(err).Warnf("[%s] Subscription failed %d times in a row, falling back to polling every %s",
+				w.networkConfig.Name, failures, w.networkConfig.PollInterval)
+			w.source = newPollingSource(w.client, w.networkConfig, w.logger)
+			failures = 0
+		} else {
+			w.logger.WithError(err).Warnf("[%s] Head source %s failed, restarting in %s",
+				w.networkConfig.Name, w.source.Name(), restartDelay)
+		}
+
+		select {
+		case <-time.After(restartDelay):
+		case <-ctx.Done():
+			return nil
+		}
+
+		// Replay the blocks mined while the source was down
+		latestBlock, err := w.client.GetLatestBlockNumber(ctx)
+		if err != nil {
+			w.logger.WithError(err).Warnf("[%s] Failed to get latest block number", w.networkConfig.Name)
+			continue
+		}
+		if err := w.catchUp(ctx, latestBlock, out); err != nil {
+			w.logger.WithError(err).Warnf("[%s] Failed to catch up", w.networkConfig.Name)
+		}
 	}
+}
 
-	// Track pending blocks
-	pending := make(map[uint64]*types.Header)
+// follow runs the head source and handles its headers until the source fails
+// or ctx is cancelled. It returns how many headers were received.
+func (w *Watcher) follow(ctx context.Context, headers chan *types.Header, out *BlockQueue) (int, error) {
+	sourceCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- w.source.Run(sourceCtx, headers)
+	}()
+
+	received := 0
 	for {
 		select {
 		case <-ctx.Done():
-			w.logger.Infof("[%s] Watcher stopped", w.networkConfig.Name)
-			return nil
+			return received, ctx.Err()
 
 		case err := <-errCh:
-			if err != nil {
-				w.logger.WithError(err).Warnf("[%s] Subscription error, retrying in 10s", w.networkConfig.Name)
-				select {
-				case <-time.After(10 * time.Second):
-					return w.Start(ctx, out) // restart watcher
-				case <-ctx.Done():
-					return nil
-				}
+			if err == nil {
+				err = fmt.Errorf("head source %s stopped", w.source.Name())
 			}
+			return received, err
 
 		case header := <-headers:
 			if header == nil {
 				continue
 			}
+			received++
+
+			w.logger.Debugf("[%s] New header: block=%d hash=%s",
+				w.networkConfig.Name, header.Number.Uint64(), header.Hash().Hex())
 
-			w.logger.Debugf("[%s] New header: block=%d", w.networkConfig.Name, header.Number.Uint64())
-
-			// Store new block header in pending
-			pending[header.Number.Uint64()] = header
-			currentHead := header.Number.Uint64()
-
-			// Process confirmed blocks
-			for blockNum, blockHeader := range pending {
-				if currentHead >= blockNum+uint64(w.confirmations) {
-					// Block is now confirmed
-					if err := w.processConfirmedBlock(ctx, blockHeader, out); err != nil {
-						w.logger.WithError(err).Errorf("[%s] Failed to process confirmed block %d",
-							w.networkConfig.Name, blockNum)
-					}
-					delete(pending, blockNum)
-				}
+			if err := w.handleHeader(ctx, header, out); err != nil {
+				w.logger.WithError(err).Errorf("[%s] Failed to handle header %d",
+					w.networkConfig.Name, header.Number.Uint64())
 			}
+		}
+	}
+}
+
+// catchUp resumes from the persisted block cursor and feeds every block up to
+// latest through the regular header handling, so missed blocks are released
+// in order once confirmed. At most maxCatchUp blocks are replayed.
+func (w *Watcher) catchUp(ctx context.Context, latest uint64, out *BlockQueue) error {
+	cursor, err := w.cursorRepo.FindByChainID(ctx, w.networkConfig.ChainID)
+	if err != nil {
+		return err
+	}
+	if cursor == nil {
+		w.logger.Infof("[%s] No block cursor found, starting from live head", w.networkConfig.Name)
+		return nil
+	}
 
-			// Clean up old pending blocks (older than 100 blocks)
-			for blockNum := range pending {
-				if currentHead > blockNum+100 {
-					w.logger.Warnf("[%s] Dropping old pending block %d", w.networkConfig.Name, blockNum)
-					delete(pending, blockNum)
-				}
+	from := uint64(cursor.BlockNumber) + 1
+	if w.lastEmitted == 0 {
+		w.lastEmitted = uint64(cursor.BlockNumber)
+		w.lastEmittedHash = common.HexToHash(cursor.BlockHash)
+
+		// The cursor block may have been reorged out while the worker was down
+		if err := w.retractOrphans(ctx, w.lastEmittedHash, out); err != nil {
+			return err
+		}
+		from = w.lastEmitted + 1
+	} else if w.lastEmitted >= from {
+		from = w.lastEmitted + 1 // already sent, the processor has not caught up yet
+	}
+
+	if latest < from {
+		return nil
+	}
+
+	if w.maxCatchUp > 0 && latest-from+1 > uint64(w.maxCatchUp) {
+		skipped := latest - from + 1 - uint64(w.maxCatchUp)
+		w.logger.Warnf("[%s] %d blocks behind, skipping %d blocks beyond the catch-up window of %d",
+			w.networkConfig.Name, latest-from+1, skipped, w.maxCatchUp)
+		metrics.BlocksDropped.WithLabelValues(w.networkConfig.Name, "catchup_window").Add(float64(skipped))
+		from += skipped
+		w.lastEmitted = from - 1
+		w.lastEmittedHash = common.Hash{}
+	}
+
+	w.logger.Infof("[%s] Catching up blocks %d..%d", w.networkConfig.Name, from, latest)
+
+	for number := from; number <= latest; number++ {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		header, err := w.client.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			return fmt.Errorf("failed to get header %d: %w", number, err)
+		}
+
+		if err := w.handleHeader(ctx, header, out); err != nil {
+			w.logger.WithError(err).Errorf("[%s] Failed to handle header %d", w.networkConfig.Name, number)
+		}
+	}
+
+	return nil
+}
+
+// retractOrphans walks back from an emitted block, such as the persisted
+// cursor, while it is no longer canonical, emitting a reorged event for each
+// orphaned block.
+func (w *Watcher) retractOrphans(ctx context.Context, hash common.Hash, out *BlockQueue) error {
+	for depth := 0; depth < reorgWindow; depth++ {
+		orphan, err := w.client.HeaderByHash(ctx, hash)
+		if err != nil {
+			w.logger.WithError(err).Warnf("[%s] Cannot load emitted block %s, assuming it is canonical",
+				w.networkConfig.Name, hash.Hex())
+			return nil
+		}
+
+		canonical, err := w.client.HeaderByNumber(ctx, orphan.Number)
+		if err != nil {
+			return fmt.Errorf("failed to get header %d: %w", orphan.Number.Uint64(), err)
+		}
+		if canonical.Hash() == orphan.Hash() {
+			return nil
+		}
+
+		if err := w.emitReorged(ctx, []*types.Header{orphan}, out); err != nil {
+			return err
+		}
+		hash = orphan.ParentHash
+	}
+	return nil
+}
+
+// handleHeader links a new header into the canonical chain, retracts orphaned
+// blocks and releases every block that reached the confirmation depth.
+func (w *Watcher) handleHeader(ctx context.Context, header *types.Header, out *BlockQueue) error {
+	w.observeHead(header.Number.Uint64())
+	defer w.observeLag()
+
+	orphaned, err := w.updateChain(ctx, header)
+	if err != nil {
+		return err
+	}
+
+	if err := w.emitReorged(ctx, orphaned, out); err != nil {
+		return err
+	}
+
+	confirmed, ok := w.confirmedHeight(ctx)
+
+	// Blocks below the tracked chain that were never released come first
+	if ok {
+		if err := w.fillGap(ctx, confirmed, out); err != nil {
+			return err
+		}
+	}
+
+	for _, blockHeader := range w.chain.headers {
+		if !ok {
+			break
+		}
+		blockNum := blockHeader.Number.Uint64()
+		if w.lastEmitted != 0 && blockNum <= w.lastEmitted {
+			continue
+		}
+		if w.lastEmitted != 0 && blockNum != w.lastEmitted+1 {
+			break // the gap could not be filled completely, never skip a height
+		}
+		if blockNum > confirmed {
+			break
+		}
+
+		// Stop at the first failure so blocks are never released out of order;
+		// the next header retries from here.
+		if err := w.release(ctx, blockHeader, out); err != nil {
+			return err
+		}
+	}
+
+	if w.networkConfig.NotifyInclusion {
+		w.emitIncluded(ctx, confirmed, ok, out)
+	}
+
+	// Headers that fell out of the reorg window before being released are
+	// fetched again by fillGap on the next header
+	for _, dropped := range w.chain.trim() {
+		if dropped.Number.Uint64() > w.lastEmitted {
+			metrics.BlocksDropped.WithLabelValues(w.networkConfig.Name, "reorg_window").Inc()
+			w.logger.Debugf("[%s] Pending block %d left the reorg window, will be refilled",
+				w.networkConfig.Name, dropped.Number.Uint64())
+		}
+	}
+
+	return nil
+}
+
+// fillGap releases the confirmed blocks between the last emitted block and the
+// start of the tracked chain. Such a gap opens when chain tracking is reset
+// after a long reorg or outage, when pending headers left the reorg window, or
+// when the confirmation tag lags further behind the head than the window.
+// The missing headers are walked back from the tracked chain by parent hash, so
+// the filled blocks are guaranteed to be its ancestors.
+func (w *Watcher) fillGap(ctx context.Context, confirmed uint64, out *BlockQueue) error {
+	first := w.chain.first()
+	if first == nil {
+		return nil
+	}
+
+	start := w.lastEmitted // highest block that must not be filled
+	if start == 0 {
+		// Nothing released yet, begin at the newest confirmed block
+		if confirmed == 0 {
+			return nil
+		}
+		start = confirmed - 1
+	}
+	if first.Number.Uint64() <= start+1 {
+		return nil
+	}
+
+	var gap []*types.Header // highest first
+	hash := first.ParentHash
+	for number := first.Number.Uint64() - 1; number > start; number-- {
+		header, err := w.client.HeaderByHash(ctx, hash)
+		if err != nil {
+			return fmt.Errorf("failed to get header %d: %w", number, err)
+		}
+		gap = append(gap, header)
+		hash = header.ParentHash
+	}
+
+	// The walk must end on the last emitted block, otherwise it was reorged out
+	if w.lastEmitted != 0 && w.lastEmittedHash != (common.Hash{}) && hash != w.lastEmittedHash {
+		w.logger.Warnf("[%s] Emitted block %d is no longer an ancestor of the chain, retracting",
+			w.networkConfig.Name, w.lastEmitted)
+		if err := w.retractOrphans(ctx, w.lastEmittedHash, out); err != nil {
+			return err
+		}
+		return fmt.Errorf("block %d was reorged below the tracked chain, gap is refilled on the next header", w.lastEmitted+1)
+	}
+
+	w.logger.Infof("[%s] Filling gap of %d blocks (%d..%d)",
+		w.networkConfig.Name, len(gap), start+1, first.Number.Uint64()-1)
+
+	for i := len(gap) - 1; i >= 0; i-- {
+		if gap[i].Number.Uint64() > confirmed {
+			return nil
+		}
+		if err := w.release(ctx, gap[i], out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// release hands a confirmed block downstream and records it as emitted
+func (w *Watcher) release(ctx context.Context, header *types.Header, out *BlockQueue) error {
+	blockNum := header.Number.Uint64()
+	if err := w.processBlock(ctx, BlockEventConfirmed, header, out); err != nil {
+		return fmt.Errorf("failed to process confirmed block %d: %w", blockNum, err)
+	}
+	w.lastEmitted = blockNum
+	w.lastEmittedHash = header.Hash()
+
+	metrics.BlocksConfirmed.WithLabelValues(w.networkConfig.Name).Inc()
+	metrics.ConfirmedBlock.WithLabelValues(w.networkConfig.Name).Set(float64(blockNum))
+
+	w.mu.Lock()
+	w.status.Confirmed = blockNum
+	w.status.ConfirmedAt = time.Now()
+	w.mu.Unlock()
+	return nil
+}
+
+// Status returns the watcher's current progress
+func (w *Watcher) Status() Status {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.status
+}
+
+// observeHead records the highest block number seen on the chain
+func (w *Watcher) observeHead(number uint64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.status.HeadSource = w.source.Name()
+	if number > w.head {
+		w.head = number
+		w.status.Head = number
+		w.status.HeadSeenAt = time.Now()
+		metrics.ChainHead.WithLabelValues(w.networkConfig.Name).Set(float64(number))
+	}
+}
+
+// observeLag records how far confirmed blocks trail the chain head
+func (w *Watcher) observeLag() {
+	if w.lastEmitted == 0 || w.head < w.lastEmitted {
+		return
+	}
+	metrics.HeadLag.WithLabelValues(w.networkConfig.Name).Set(float64(w.head - w.lastEmitted))
+}
+
+// updateChain adds header to the canonical chain. When the header does not
+// build on the current tip, its ancestors are fetched until a common ancestor
+// with the tracked chain is found. The headers that are no longer canonical
+// are returned, highest first.
+func (w *Watcher) updateChain(ctx context.Context, header *types.Header) ([]*types.Header, error) {
+	tip := w.chain.tip()
+	if tip == nil {
+		w.chain.append(header)
+		return nil, nil
+	}
+
+	if known := w.chain.get(header.Number.Uint64()); known != nil && known.Hash() == header.Hash() {
+		return nil, nil // already canonical, e.g. replayed after a resubscribe
+	}
+
+	branch := []*types.Header{header}
+	for {
+		cur := branch[0]
+		parentNum := cur.Number.Uint64() - 1
+
+		if parent := w.chain.get(parentNum); parent != nil && parent.Hash() == cur.ParentHash {
+			break // common ancestor found
+		}
+
+		if parentNum < w.chain.first().Number.Uint64() || len(branch) > reorgWindow {
+			w.logger.Errorf("[%s] Reorg or gap at block %d exceeds the %d block window, resetting chain tracking",
+				w.networkConfig.Name, header.Number.Uint64(), reorgWindow)
+			orphaned, err := w.staleHeaders(ctx)
+			if err != nil {
+				return nil, err
 			}
+			w.chain.reset()
+			w.chain.append(header)
+			return orphaned, nil
 		}
+
+		parent, err := w.client.HeaderByHash(ctx, cur.ParentHash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get parent header %s: %w", cur.ParentHash.Hex(), err)
+		}
+		branch = append([]*types.Header{parent}, branch...)
+	}
+
+	ancestor := branch[0].Number.Uint64() - 1
+	orphaned := w.chain.rewind(ancestor)
+	for _, h := range branch {
+		w.chain.append(h)
+	}
+
+	if len(orphaned) > 0 {
+		w.logger.Warnf("[%s] Reorg detected: common ancestor=%d, orphaned=%d, new head=%d",
+			w.networkConfig.Name, ancestor, len(orphaned), header.Number.Uint64())
 	}
+
+	return orphaned, nil
 }
 
-func (w *Watcher) processConfirmedBlock(ctx context.Context, header *types.Header, out chan<- *types.Block) error {
+// staleHeaders compares the tracked headers with the node's canonical chain
+// and returns the ones that are no longer canonical, highest first.
+func (w *Watcher) staleHeaders(ctx context.Context) ([]*types.Header, error) {
+	var stale []*types.Header
+	for i := len(w.chain.headers) - 1; i >= 0; i-- {
+		tracked := w.chain.headers[i]
+		canonical, err := w.client.HeaderByNumber(ctx, tracked.Number)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get header %d: %w", tracked.Number.Uint64(), err)
+		}
+		if canonical.Hash() == tracked.Hash() {
+			break // everything below a canonical header is canonical too
+		}
+		stale = append(stale, tracked)
+	}
+	return stale, nil
+}
+
+// emitReorged sends a reorged event for every orphaned block that was already
+// released downstream as included or confirmed. Headers are expected highest first.
+func (w *Watcher) emitReorged(ctx context.Context, orphaned []*types.Header, out *BlockQueue) error {
+	for _, header := range orphaned {
+		blockNum := header.Number.Uint64()
+		if blockNum > max(w.lastEmitted, w.lastIncluded) {
+			continue // never left the watcher, nothing to retract
+		}
+		if w.lastIncluded >= blockNum {
+			w.lastIncluded = blockNum - 1
+		}
+		if blockNum > w.lastEmitted {
+			// Only announced as included, the confirmed position is unaffected
+			w.logger.Warnf("[%s] Reorged included block=%d hash=%s", w.networkConfig.Name, blockNum, header.Hash().Hex())
+			metrics.BlocksReorged.WithLabelValues(w.networkConfig.Name).Inc()
+			event := &BlockEvent{Type: BlockEventReorged, NetworkConfig: w.networkConfig, Header: header}
+			if err := w.push(ctx, out, event); err != nil {
+				return err
+			}
+			continue
+		}
+
+		w.logger.Warnf("[%s] Reorged block=%d hash=%s", w.networkConfig.Name, blockNum, header.Hash().Hex())
+		metrics.BlocksReorged.WithLabelValues(w.networkConfig.Name).Inc()
+
+		event := &BlockEvent{Type: BlockEventReorged, NetworkConfig: w.networkConfig, Header: header}
+		if err := w.push(ctx, out, event); err != nil {
+			return err
+		}
+		w.lastEmitted = blockNum - 1
+		w.lastEmittedHash = header.ParentHash
+	}
+	return nil
+}
+
+// processBlock fetches a block with its transactions and hands it downstream
+// as eventType. Each block starts a trace that its processing and webhook
+// deliveries continue.
+func (w *Watcher) processBlock(ctx context.Context, eventType BlockEventType, header *types.Header, out *BlockQueue) (err error) {
+	ctx, span := tracing.Start(ctx, "watcher.block",
+		attribute.String("network", w.networkConfig.Name),
+		attribute.String("block.event", string(eventType)),
+		attribute.Int64("block.number", header.Number.Int64()),
+		attribute.String("block.hash", header.Hash().Hex()),
+	)
+	defer func() { tracing.End(span, err) }()
+
 	// Get basic block via client method
-	block, _, err := w.client.GetBlockWithTransactions(ctx, header.Number)
+	block, details, err := w.client.GetBlockWithTransactions(ctx, header.Number)
 	if err != nil {
 		return fmt.Errorf("failed to get block %d: %w", header.Number.Uint64(), err)
 	}
 
-	w.logger.Infof("[%s] Confirmed block=%d hash=%s txs=%d",
-		w.networkConfig.Name, block.Number().Uint64(), block.Hash().Hex(), len(block.Transactions()))
+	if block.Hash() != header.Hash() {
+		return fmt.Errorf("block %d changed from %s to %s, waiting for the chain to settle",
+			header.Number.Uint64(), header.Hash().Hex(), block.Hash().Hex())
+	}
 
-	// Send to processor (non-blocking)
-	select {
-	case out <- block:
-		return nil
-	default:
-		w.logger.Warnf("[%s] Block processor channel full, dropping block %d",
-			w.networkConfig.Name, block.Number().Uint64())
-		return fmt.Errorf("block processor channel full")
+	w.logger.Infof("[%s] %s block=%d hash=%s txs=%d",
+		w.networkConfig.Name, eventType, block.Number().Uint64(), block.Hash().Hex(), len(block.Transactions()))
+
+	event := &BlockEvent{
+		Type:               eventType,
+		NetworkConfig:      w.networkConfig,
+		Block:              block,
+		Header:             header,
+		TransactionDetails: details,
+		Trace:              span.SpanContext(),
+	}
+
+	return w.push(ctx, out, event)
+}
+
+// push hands an event to the processor, waiting while its queue is full
+func (w *Watcher) push(ctx context.Context, out *BlockQueue, event *BlockEvent) error {
+	waited, err := out.Push(ctx, event)
+	if err != nil {
+		return err
+	}
+	if waited >= slowPushThreshold {
+		w.logger.Warnf("[%s] Block queue full, waited %s to hand over block %d",
+			w.networkConfig.Name, waited.Round(time.Millisecond), event.Header.Number.Uint64())
 	}
+	return nil
 }
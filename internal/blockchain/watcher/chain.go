@@ -0,0 +1,76 @@
+package watcher
+
+import (
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// canonicalChain keeps the most recent headers of the canonical chain,
+// ordered by ascending block number and linked through their parent hashes.
+type canonicalChain struct {
+	headers []*types.Header
+	maxSize int
+}
+
+func newCanonicalChain(maxSize int) *canonicalChain {
+	return &canonicalChain{maxSize: maxSize}
+}
+
+// tip returns the highest known header, or nil when the chain is empty
+func (c *canonicalChain) tip() *types.Header {
+	if len(c.headers) == 0 {
+		return nil
+	}
+	return c.headers[len(c.headers)-1]
+}
+
+// first returns the lowest header still tracked, or nil when the chain is empty
+func (c *canonicalChain) first() *types.Header {
+	if len(c.headers) == 0 {
+		return nil
+	}
+	return c.headers[0]
+}
+
+// get returns the tracked header at the given height, or nil if it is not tracked
+func (c *canonicalChain) get(number uint64) *types.Header {
+	first := c.first()
+	if first == nil {
+		return nil
+	}
+	start := first.Number.Uint64()
+	if number < start || number >= start+uint64(len(c.headers)) {
+		return nil
+	}
+	return c.headers[number-start]
+}
+
+// append adds a header on top of the current tip
+func (c *canonicalChain) append(header *types.Header) {
+	c.headers = append(c.headers, header)
+}
+
+// rewind removes every header above the given height and returns them, highest first
+func (c *canonicalChain) rewind(number uint64) []*types.Header {
+	var removed []*types.Header
+	for len(c.headers) > 0 && c.tip().Number.Uint64() > number {
+		removed = append(removed, c.tip())
+		c.headers = c.headers[:len(c.headers)-1]
+	}
+	return removed
+}
+
+// reset drops every tracked header
+func (c *canonicalChain) reset() {
+	c.headers = nil
+}
+
+// trim drops the oldest headers so that at most maxSize are kept, returning the dropped ones
+func (c *canonicalChain) trim() []*types.Header {
+	if len(c.headers) <= c.maxSize {
+		return nil
+	}
+	excess := len(c.headers) - c.maxSize
+	dropped := c.headers[:excess]
+	c.headers = append([]*types.Header(nil), c.headers[excess:]...)
+	return dropped
+}
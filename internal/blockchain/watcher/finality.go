@@ -0,0 +1,88 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"evm-tx-watcher/internal/config"
+)
+
+// policyName describes the network's confirmation policy for logs
+func (w *Watcher) policyName() string {
+	if w.networkConfig.ConfirmationPolicy == config.ConfirmationDepth {
+		return fmt.Sprintf("depth(%d)", w.networkConfig.Confirmations)
+	}
+	return w.networkConfig.ConfirmationPolicy
+}
+
+// confirmedHeight returns the highest block number that may be released under
+// the network's confirmation policy; ok is false while no block qualifies
+func (w *Watcher) confirmedHeight(ctx context.Context) (uint64, bool) {
+	tip := w.chain.tip().Number.Uint64()
+
+	if w.networkConfig.ConfirmationPolicy == config.ConfirmationDepth {
+		depth := uint64(w.networkConfig.Confirmations)
+		if tip < depth {
+			return 0, false
+		}
+		return tip - depth, true
+	}
+
+	w.refreshFinality(ctx)
+	if w.finalized == 0 {
+		return 0, false
+	}
+	return min(w.finalized, tip), true
+}
+
+// refreshFinality polls the safe or finalized block tag, at most once per poll
+// interval. A tagged block that conflicts with the tracked chain is ignored
+// until the chain has caught up with it.
+func (w *Watcher) refreshFinality(ctx context.Context) {
+	if time.Since(w.finalityCheckedAt) < w.networkConfig.PollInterval {
+		return
+	}
+	w.finalityCheckedAt = time.Now()
+
+	tag := rpc.SafeBlockNumber
+	if w.networkConfig.ConfirmationPolicy == config.ConfirmationFinalized {
+		tag = rpc.FinalizedBlockNumber
+	}
+
+	header, err := w.client.HeaderByNumber(ctx, big.NewInt(tag.Int64()))
+	if err != nil {
+		w.logger.WithError(err).Warnf("[%s] Failed to get %s block", w.networkConfig.Name, tag)
+		return
+	}
+
+	number := header.Number.Uint64()
+	if known := w.chain.get(number); known != nil && known.Hash() != header.Hash() {
+		w.logger.Debugf("[%s] %s block %d is not on the tracked chain yet", w.networkConfig.Name, tag, number)
+		return
+	}
+	if number > w.finalized {
+		w.finalized = number
+	}
+}
+
+// emitIncluded announces new canonical blocks that are not confirmed yet, so
+// their transactions can be reported at inclusion and again once confirmed.
+// Failures are retried on the next header.
+func (w *Watcher) emitIncluded(ctx context.Context, confirmed uint64, ok bool, out *BlockQueue) {
+	for _, header := range w.chain.headers {
+		blockNum := header.Number.Uint64()
+		if blockNum <= max(w.lastEmitted, w.lastIncluded) || (ok && blockNum <= confirmed) {
+			continue
+		}
+
+		if err := w.processBlock(ctx, BlockEventIncluded, header, out); err != nil {
+			w.logger.WithError(err).Warnf("[%s] Failed to announce included block %d", w.networkConfig.Name, blockNum)
+			return
+		}
+		w.lastIncluded = blockNum
+	}
+}
@@ -0,0 +1,155 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"evm-tx-watcher/internal/blockchain/client"
+	"evm-tx-watcher/internal/config"
+	"evm-tx-watcher/internal/util"
+)
+
+const (
+	// pollFailureLimit consecutive failed polls make the polling source give up
+	pollFailureLimit = 5
+	// maxSynthesizedHeaders bounds how many skipped headers one poll fetches
+	maxSynthesizedHeaders = reorgWindow
+)
+
+// HeadSource delivers new chain heads to the watcher
+type HeadSource interface {
+	// Name identifies the source in logs
+	Name() string
+	// Run sends new headers to out in ascending order until ctx is cancelled,
+	// returning nil, or until the source fails, returning the error
+	Run(ctx context.Context, out chan<- *types.Header) error
+}
+
+// newHeadSource picks the head source configured for the network; in auto
+// mode subscriptions are used whenever a WebSocket URL is available
+func newHeadSource(c *client.Client, network config.NetworkConfig, logger *util.Logger) HeadSource {
+	switch network.HeadSource {
+	case config.HeadSourceSubscribe:
+		return &subscriptionSource{client: c}
+	case config.HeadSourcePoll:
+		return newPollingSource(c, network, logger)
+	}
+	if len(network.WSURLs) > 0 {
+		return &subscriptionSource{client: c}
+	}
+	return newPollingSource(c, network, logger)
+}
+
+// subscriptionSource follows the chain with an eth_subscribe newHeads subscription
+type subscriptionSource struct {
+	client *client.Client
+}
+
+func (s *subscriptionSource) Name() string { return config.HeadSourceSubscribe }
+
+func (s *subscriptionSource) Run(ctx context.Context, out chan<- *types.Header) error {
+	errCh, err := s.client.SubscribeNewHeads(ctx, out)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to new heads: %w", err)
+	}
+
+	select {
+	case err := <-errCh:
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err == nil {
+			err = errors.New("subscription closed")
+		}
+		return err
+	case <-ctx.Done():
+		return nil
+	}
+}
+
+// pollingSource follows the chain by polling the latest block number and
+// fetching the headers of every block mined since the previous poll
+type pollingSource struct {
+	client   *client.Client
+	network  string
+	interval time.Duration
+	logger   *util.Logger
+	last     uint64 // highest header sent
+}
+
+func newPollingSource(c *client.Client, network config.NetworkConfig, logger *util.Logger) *pollingSource {
+	return &pollingSource{
+		client:   c,
+		network:  network.Name,
+		interval: network.PollInterval,
+		logger:   logger,
+	}
+}
+
+func (p *pollingSource) Name() string { return config.HeadSourcePoll }
+
+func (p *pollingSource) Run(ctx context.Context, out chan<- *types.Header) error {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	failures := 0
+	for {
+		if err := p.poll(ctx, out); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			failures++
+			if failures >= pollFailureLimit {
+				return fmt.Errorf("polling failed %d times in a row: %w", failures, err)
+			}
+			p.logger.WithError(err).Warnf("[%s] Head poll failed", p.network)
+		} else {
+			failures = 0
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll sends the headers of every block above the last one sent, up to the
+// current head. After a long stall only the newest maxSynthesizedHeaders are sent.
+func (p *pollingSource) poll(ctx context.Context, out chan<- *types.Header) error {
+	head, err := p.client.GetLatestBlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	if p.last != 0 && head <= p.last {
+		return nil
+	}
+
+	from := p.last + 1
+	if p.last == 0 {
+		from = head // the watcher caught up before the source started
+	} else if head-p.last > maxSynthesizedHeaders {
+		from = head - maxSynthesizedHeaders + 1
+	}
+
+	for number := from; number <= head; number++ {
+		header, err := p.client.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			return fmt.Errorf("failed to get header %d: %w", number, err)
+		}
+
+		select {
+		case out <- header:
+			p.last = number
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package watcher
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// slowPushThreshold is how long a push may wait before the backpressure is logged
+const slowPushThreshold = time.Second
+
+// QueueStats is a snapshot of a block queue for operators
+type QueueStats struct {
+	Network        string  `json:"network"`
+	Depth          int     `json:"depth"`
+	Capacity       int     `json:"capacity"`
+	Pushed         int64   `json:"pushed"`
+	BlockedPushes  int64   `json:"blocked_pushes"`
+	BlockedSeconds float64 `json:"blocked_seconds"`
+	LastProcessed  uint64  `json:"last_processed"` // highest confirmed block the processor finished
+}
+
+// BlockQueue is the bounded queue between one network's watcher and the
+// processor. Pushing to a full queue blocks, so a slow processor slows the
+// watcher down instead of losing blocks.
+type BlockQueue struct {
+	network string
+	events  chan *BlockEvent
+
+	pushed        atomic.Int64
+	blockedPushes atomic.Int64
+	blockedNanos  atomic.Int64
+	lastProcessed atomic.Uint64
+}
+
+func NewBlockQueue(network string, size int) *BlockQueue {
+	return &BlockQueue{
+		network: network,
+		events:  make(chan *BlockEvent, size),
+	}
+}
+
+// Network returns the name of the network the queue belongs to
+func (q *BlockQueue) Network() string {
+	return q.network
+}
+
+// Events returns the channel the processor consumes, in push order
+func (q *BlockQueue) Events() <-chan *BlockEvent {
+	return q.events
+}
+
+// Push enqueues event, waiting for room when the queue is full. It returns the
+// time spent waiting and fails only when ctx is cancelled.
+func (q *BlockQueue) Push(ctx context.Context, event *BlockEvent) (time.Duration, error) {
+	select {
+	case q.events <- event:
+		q.pushed.Add(1)
+		return 0, nil
+	default:
+	}
+
+	started := time.Now()
+	select {
+	case q.events <- event:
+		waited := time.Since(started)
+		q.pushed.Add(1)
+		q.blockedPushes.Add(1)
+		q.blockedNanos.Add(int64(waited))
+		return waited, nil
+	case <-ctx.Done():
+		q.blockedNanos.Add(int64(time.Since(started)))
+		return time.Since(started), ctx.Err()
+	}
+}
+
+// Stats returns the current depth and backpressure counters
+func (q *BlockQueue) Stats() QueueStats {
+	return QueueStats{
+		Network:        q.network,
+		Depth:          len(q.events),
+		Capacity:       cap(q.events),
+		Pushed:         q.pushed.Load(),
+		BlockedPushes:  q.blockedPushes.Load(),
+		BlockedSeconds: time.Duration(q.blockedNanos.Load()).Seconds(),
+		LastProcessed:  q.lastProcessed.Load(),
+	}
+}
+
+// MarkProcessed records that the processor finished an event taken off the queue
+func (q *BlockQueue) MarkProcessed(event *BlockEvent) {
+	if event.Type == BlockEventConfirmed {
+		q.lastProcessed.Store(event.Header.Number.Uint64())
+	}
+}
@@ -0,0 +1,48 @@
+// Package buildinfo holds the version of the running binary. The values are
+// set at build time, see the LDFLAGS of the Makefile:
+//
+//	go build -ldflags "-X evm-tx-watcher/internal/buildinfo.Version=v1.2.0" ./cmd/api
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Set with -ldflags -X
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildTime = "unknown"
+)
+
+// Info describes the running binary
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get returns the build info. Binaries built without ldflags fall back to the
+// VCS details the Go toolchain embeds, when available.
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range bi.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "unknown":
+				info.Commit = setting.Value
+			case setting.Key == "vcs.time" && info.BuildTime == "unknown":
+				info.BuildTime = setting.Value
+			}
+		}
+	}
+	return info
+}
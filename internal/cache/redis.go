@@ -36,6 +36,11 @@ func NewRedisClient(cfg *config.RedisConfig) (*RedisClient, error) {
 	return &RedisClient{client: rdb}, nil
 }
 
+// Ping checks that Redis answers
+func (r *RedisClient) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
 // Close closes the Redis connection
 func (r *RedisClient) Close() error {
 	return r.client.Close()
@@ -43,19 +48,58 @@ func (r *RedisClient) Close() error {
 
 // Cache keys
 const (
-	WatchedAddressesKey = "watched_addresses"
-	WebhookQueueKey     = "webhook_queue"
-	ProcessedBlockKey   = "processed_block:%s:%d" // network:block_number
+	WatchedAddressesKey        = "watched_addresses"
+	WatchedAddressesVersionKey = "watched_addresses:version" // bumped on every invalidation
+	ProcessedBlockKey          = "processed_block:%s:%d"     // network:block_number
+	BlockCursorKey             = "block_cursor:%s"           // network
+	TokenMetadataKey           = "token:%d:%s"               // chain_id:address
 )
 
-// CacheWatchedAddresses caches the list of watched addresses
-func (r *RedisClient) CacheWatchedAddresses(ctx context.Context, addresses []domain.WatchedAddress) error {
+const (
+	// WatchedAddressesTTL bounds how long the watched address list is cached
+	WatchedAddressesTTL = 5 * time.Minute
+	// TokenMetadataTTL bounds how long resolved token metadata is cached
+	TokenMetadataTTL = 24 * time.Hour
+)
+
+// cacheWatchedAddresses stores the list only while the version is still the
+// one read before the list was loaded
+var cacheWatchedAddresses = redis.NewScript(`
+if (redis.call('GET', KEYS[2]) or '0') ~= ARGV[1] then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+return 1
+`)
+
+// WatchedAddressesVersion returns the number of invalidations so far. Read it
+// before loading the list from Postgres and pass it to CacheWatchedAddresses.
+func (r *RedisClient) WatchedAddressesVersion(ctx context.Context) (int64, error) {
+	version, err := r.client.Get(ctx, WatchedAddressesVersionKey).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get watched addresses version: %w", err)
+	}
+	return version, nil
+}
+
+// CacheWatchedAddresses caches the list of watched addresses loaded at version.
+// A list loaded before a concurrent invalidation may miss the change and is
+// not cached.
+func (r *RedisClient) CacheWatchedAddresses(ctx context.Context, addresses []domain.WatchedAddress, version int64) error {
 	data, err := json.Marshal(addresses)
 	if err != nil {
 		return fmt.Errorf("failed to marshal watched addresses: %w", err)
 	}
 
-	return r.client.Set(ctx, WatchedAddressesKey, data, 5*time.Minute).Err()
+	err = cacheWatchedAddresses.Run(ctx, r.client, []string{WatchedAddressesKey, WatchedAddressesVersionKey},
+		version, data, WatchedAddressesTTL.Milliseconds()).Err()
+	if err != nil {
+		return fmt.Errorf("failed to cache watched addresses: %w", err)
+	}
+	return nil
 }
 
 // GetWatchedAddresses retrieves cached watched addresses
@@ -76,41 +120,18 @@ func (r *RedisClient) GetWatchedAddresses(ctx context.Context) ([]domain.Watched
 	return addresses, nil
 }
 
-// InvalidateWatchedAddresses removes watched addresses from cache
+// InvalidateWatchedAddresses removes watched addresses from cache and bumps
+// the version, so lists loaded before the change are not cached afterwards
 func (r *RedisClient) InvalidateWatchedAddresses(ctx context.Context) error {
-	return r.client.Del(ctx, WatchedAddressesKey).Err()
-}
-
-// QueueWebhookDelivery adds a webhook delivery to the queue
-func (r *RedisClient) QueueWebhookDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
-	data, err := json.Marshal(delivery)
-	if err != nil {
-		return fmt.Errorf("failed to marshal webhook delivery: %w", err)
-	}
-
-	return r.client.LPush(ctx, WebhookQueueKey, data).Err()
-}
-
-// DequeueWebhookDelivery gets a webhook delivery from the queue (blocking)
-func (r *RedisClient) DequeueWebhookDelivery(ctx context.Context, timeout time.Duration) (*domain.WebhookDelivery, error) {
-	result, err := r.client.BRPop(ctx, timeout, WebhookQueueKey).Result()
-	if err == redis.Nil {
-		return nil, nil // Timeout
-	}
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Incr(ctx, WatchedAddressesVersionKey)
+		pipe.Del(ctx, WatchedAddressesKey)
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to dequeue webhook delivery: %w", err)
-	}
-
-	if len(result) < 2 {
-		return nil, fmt.Errorf("invalid queue result format")
+		return fmt.Errorf("failed to invalidate watched addresses: %w", err)
 	}
-
-	var delivery domain.WebhookDelivery
-	if err := json.Unmarshal([]byte(result[1]), &delivery); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal webhook delivery: %w", err)
-	}
-
-	return &delivery, nil
+	return nil
 }
 
 // SetProcessedBlock marks a block as processed for a network
@@ -132,8 +153,62 @@ func (r *RedisClient) IsBlockProcessed(ctx context.Context, network string, bloc
 	return true, nil
 }
 
-// GetQueueLength returns the length of the webhook queue
-func (r *RedisClient) GetQueueLength(ctx context.Context) (int64, error) {
-	return r.client.LLen(ctx, WebhookQueueKey).Result()
+// SetBlockCursor mirrors the last processed block of a network
+func (r *RedisClient) SetBlockCursor(ctx context.Context, cursor *domain.BlockCursor) error {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return fmt.Errorf("failed to marshal block cursor: %w", err)
+	}
+
+	key := fmt.Sprintf(BlockCursorKey, cursor.Network)
+	return r.client.Set(ctx, key, data, 0).Err()
+}
+
+// GetBlockCursor retrieves the mirrored block cursor of a network
+func (r *RedisClient) GetBlockCursor(ctx context.Context, network string) (*domain.BlockCursor, error) {
+	key := fmt.Sprintf(BlockCursorKey, network)
+	data, err := r.client.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return nil, nil // Cache miss
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block cursor from cache: %w", err)
+	}
+
+	var cursor domain.BlockCursor
+	if err := json.Unmarshal([]byte(data), &cursor); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal block cursor: %w", err)
+	}
+
+	return &cursor, nil
 }
 
+// SetTokenMetadata caches the metadata of a token contract
+func (r *RedisClient) SetTokenMetadata(ctx context.Context, token *domain.Token) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("failed to marshal token metadata: %w", err)
+	}
+
+	key := fmt.Sprintf(TokenMetadataKey, token.ChainID, token.Address)
+	return r.client.Set(ctx, key, data, TokenMetadataTTL).Err()
+}
+
+// GetTokenMetadata retrieves cached token metadata
+func (r *RedisClient) GetTokenMetadata(ctx context.Context, chainID int64, address string) (*domain.Token, error) {
+	key := fmt.Sprintf(TokenMetadataKey, chainID, address)
+	data, err := r.client.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return nil, nil // Cache miss
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token metadata from cache: %w", err)
+	}
+
+	var token domain.Token
+	if err := json.Unmarshal([]byte(data), &token); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal token metadata: %w", err)
+	}
+
+	return &token, nil
+}
@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Stream event keys. The Redis stream keeps a bounded history for clients
+// resuming after a disconnect; the channel fans live events out to every API replica.
+const (
+	StreamEventsKey     = "stream_events"
+	StreamEventsChannel = "stream_events:live"
+)
+
+// appendStreamEvent adds an event to the history and publishes it in one step,
+// so events reach subscribers in the order of their stream IDs
+var appendStreamEvent = redis.NewScript(`
+local id = redis.call('XADD', KEYS[1], 'MAXLEN', '~', ARGV[1], '*', 'data', ARGV[2])
+redis.call('PUBLISH', KEYS[2], id .. '\n' .. ARGV[2])
+return id
+`)
+
+// StreamMessage is a stream event as stored in Redis
+type StreamMessage struct {
+	ID   string
+	Data string
+}
+
+// AppendStreamEvent records an event, keeping about maxLen of the newest ones,
+// and publishes it to the live channel. It returns the event's stream ID.
+func (r *RedisClient) AppendStreamEvent(ctx context.Context, maxLen int64, data []byte) (string, error) {
+	id, err := appendStreamEvent.Run(ctx, r.client, []string{StreamEventsKey, StreamEventsChannel}, maxLen, data).Text()
+	if err != nil {
+		return "", fmt.Errorf("failed to append stream event: %w", err)
+	}
+	return id, nil
+}
+
+// StreamEventsAfter returns up to count recorded events newer than afterID
+func (r *RedisClient) StreamEventsAfter(ctx context.Context, afterID string, count int64) ([]StreamMessage, error) {
+	entries, err := r.client.XRangeN(ctx, StreamEventsKey, "("+afterID, "+", count).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stream events: %w", err)
+	}
+
+	messages := make([]StreamMessage, 0, len(entries))
+	for _, entry := range entries {
+		data, _ := entry.Values["data"].(string)
+		messages = append(messages, StreamMessage{ID: entry.ID, Data: data})
+	}
+	return messages, nil
+}
+
+// SubscribeStreamEvents subscribes to live stream events. The caller closes
+// the subscription; go-redis reconnects it on connection loss.
+func (r *RedisClient) SubscribeStreamEvents(ctx context.Context) *redis.PubSub {
+	return r.client.Subscribe(ctx, StreamEventsChannel)
+}
+
+// ParseStreamMessage splits a live channel payload into the event ID and data
+func ParseStreamMessage(payload string) (StreamMessage, error) {
+	id, data, ok := strings.Cut(payload, "\n")
+	if !ok {
+		return StreamMessage{}, fmt.Errorf("malformed stream message")
+	}
+	return StreamMessage{ID: id, Data: data}, nil
+}
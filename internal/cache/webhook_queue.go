@@ -0,0 +1,168 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// Webhook queue keys. Deliveries are queued by ID on a Redis stream read by the
+// dispatchers' consumer group; an entry stays pending until it is acknowledged,
+// so a dispatcher crashing mid-delivery does not lose it.
+const (
+	WebhookQueueKey       = "webhook_deliveries"
+	WebhookQueueGroup     = "dispatchers"
+	LegacyWebhookQueueKey = "webhook_queue" // list of serialized deliveries used before the stream
+)
+
+// migrateLegacyWebhookQueue moves the IDs of deliveries still waiting on the
+// legacy list onto the stream in one step, so none is lost in between
+var migrateLegacyWebhookQueue = redis.NewScript(`
+local moved = 0
+while true do
+	local item = redis.call('RPOP', KEYS[1])
+	if not item then
+		return moved
+	end
+	local ok, delivery = pcall(cjson.decode, item)
+	if ok and type(delivery) == 'table' and delivery.id then
+		redis.call('XADD', KEYS[2], '*', 'delivery_id', delivery.id)
+		moved = moved + 1
+	end
+end
+`)
+
+// QueuedDelivery is a queue entry. DeliveryID is uuid.Nil for malformed entries,
+// which should be acknowledged and dropped. Lease is the lease the delivery held
+// when it was queued; it is nil for entries queued before leases were recorded.
+type QueuedDelivery struct {
+	MessageID  string
+	DeliveryID uuid.UUID
+	Lease      *time.Time
+}
+
+// EnsureWebhookQueue creates the stream and its consumer group if needed and
+// moves over deliveries left on the legacy list. It returns how many were moved.
+func (r *RedisClient) EnsureWebhookQueue(ctx context.Context) (int64, error) {
+	// Start at 0 so entries added before the group existed are still read
+	err := r.client.XGroupCreateMkStream(ctx, WebhookQueueKey, WebhookQueueGroup, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return 0, fmt.Errorf("failed to create webhook queue group: %w", err)
+	}
+
+	moved, err := migrateLegacyWebhookQueue.Run(ctx, r.client, []string{LegacyWebhookQueueKey, WebhookQueueKey}).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("failed to migrate legacy webhook queue: %w", err)
+	}
+	return moved, nil
+}
+
+// QueueWebhookDelivery queues a delivery by ID along with its current lease;
+// Postgres holds the delivery itself. A dispatcher only sends the delivery while
+// it still holds that lease, so an entry the retry scheduler overtook is skipped.
+func (r *RedisClient) QueueWebhookDelivery(ctx context.Context, deliveryID uuid.UUID, lease time.Time) error {
+	err := r.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: WebhookQueueKey,
+		Values: map[string]interface{}{
+			"delivery_id": deliveryID.String(),
+			"lease":       strconv.FormatInt(lease.UnixMicro(), 10),
+		},
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("failed to queue webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// DequeueWebhookDeliveries reads up to count new entries for consumer, waiting
+// at most block. The entries stay pending until AckWebhookDelivery.
+func (r *RedisClient) DequeueWebhookDeliveries(ctx context.Context, consumer string, count int64, block time.Duration) ([]QueuedDelivery, error) {
+	streams, err := r.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    WebhookQueueGroup,
+		Consumer: consumer,
+		Streams:  []string{WebhookQueueKey, ">"},
+		Count:    count,
+		Block:    block,
+	}).Result()
+	if err == redis.Nil {
+		return nil, nil // Timeout
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to dequeue webhook deliveries: %w", err)
+	}
+
+	var queued []QueuedDelivery
+	for _, stream := range streams {
+		queued = append(queued, toQueuedDeliveries(stream.Messages)...)
+	}
+	return queued, nil
+}
+
+// ClaimStaleWebhookDeliveries takes over up to count entries that another
+// consumer read but has not acknowledged for at least minIdle, e.g. because
+// its dispatcher crashed
+func (r *RedisClient) ClaimStaleWebhookDeliveries(ctx context.Context, consumer string, minIdle time.Duration, count int64) ([]QueuedDelivery, error) {
+	var queued []QueuedDelivery
+	start := "0-0"
+	for int64(len(queued)) < count {
+		messages, next, err := r.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   WebhookQueueKey,
+			Group:    WebhookQueueGroup,
+			Consumer: consumer,
+			MinIdle:  minIdle,
+			Start:    start,
+			Count:    count - int64(len(queued)),
+		}).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to claim stale webhook deliveries: %w", err)
+		}
+		queued = append(queued, toQueuedDeliveries(messages)...)
+		if next == "0-0" {
+			break // scanned the whole pending list
+		}
+		start = next
+	}
+	return queued, nil
+}
+
+// AckWebhookDelivery acknowledges an entry once its outcome is recorded and
+// removes it, so the stream only holds deliveries still in flight
+func (r *RedisClient) AckWebhookDelivery(ctx context.Context, messageID string) error {
+	pipe := r.client.TxPipeline()
+	pipe.XAck(ctx, WebhookQueueKey, WebhookQueueGroup, messageID)
+	pipe.XDel(ctx, WebhookQueueKey, messageID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to acknowledge webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// GetQueueLength returns how many deliveries are queued or in flight
+func (r *RedisClient) GetQueueLength(ctx context.Context) (int64, error) {
+	return r.client.XLen(ctx, WebhookQueueKey).Result()
+}
+
+func toQueuedDeliveries(messages []redis.XMessage) []QueuedDelivery {
+	queued := make([]QueuedDelivery, 0, len(messages))
+	for _, message := range messages {
+		value, _ := message.Values["delivery_id"].(string)
+		deliveryID, err := uuid.Parse(value)
+		if err != nil {
+			deliveryID = uuid.Nil
+		}
+		entry := QueuedDelivery{MessageID: message.ID, DeliveryID: deliveryID}
+		if value, ok := message.Values["lease"].(string); ok {
+			if micros, err := strconv.ParseInt(value, 10, 64); err == nil {
+				lease := time.UnixMicro(micros)
+				entry.Lease = &lease
+			}
+		}
+		queued = append(queued, entry)
+	}
+	return queued
+}
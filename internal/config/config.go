@@ -3,26 +3,77 @@ package config
 import (
 	"fmt"
 	"log"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 // Config holds all configuration for the application
 type Config struct {
-	AppPort   string                   `mapstructure:"APP_PORT"`
-	LogLevel  string                   `mapstructure:"LOG_LEVEL"`
-	LogFormat string                   `mapstructure:"LOG_FORMAT"`
-	DB        DatabaseConfig           `mapstructure:",squash"`
-	Redis     RedisConfig              `mapstructure:",squash"`
-	Networks  map[string]NetworkConfig `mapstructure:"-"`
+	AppPort    string                   `mapstructure:"APP_PORT"`
+	LogLevel   string                   `mapstructure:"LOG_LEVEL"`
+	LogFormat  string                   `mapstructure:"LOG_FORMAT"`
+	DB         DatabaseConfig           `mapstructure:",squash"`
+	Redis      RedisConfig              `mapstructure:",squash"`
+	Worker     WorkerConfig             `mapstructure:",squash"`
+	Dispatcher DispatcherConfig         `mapstructure:",squash"`
+	Stream     StreamConfig             `mapstructure:",squash"`
+	Tracing    TracingConfig            `mapstructure:",squash"`
+	Networks   map[string]NetworkConfig `mapstructure:"-"`
 }
 
+// Head sources a watcher can follow the chain with
+const (
+	HeadSourceAuto      = "auto"      // subscribe when a WebSocket URL is configured, poll otherwise
+	HeadSourceSubscribe = "subscribe" // eth_subscribe newHeads
+	HeadSourcePoll      = "poll"      // poll eth_blockNumber
+)
+
+// Confirmation policies deciding when a block is final enough to notify
+const (
+	ConfirmationDepth     = "depth"     // a fixed number of blocks on top
+	ConfirmationSafe      = "safe"      // at or below the "safe" block tag
+	ConfirmationFinalized = "finalized" // at or below the "finalized" block tag
+)
+
 // NetworkConfig holds network configuration with chain ID
 type NetworkConfig struct {
-	Name    string
-	ChainID int64
-	RPC     string
+	Name               string
+	ChainID            int64
+	WSURLs             []string      // ordered by preference, serve subscriptions and calls
+	HTTPURLs           []string      // ordered by preference, serve calls only
+	HeadSource         string        // one of the HeadSource constants
+	PollInterval       time.Duration // head polling interval, roughly the block time
+	ConfirmationPolicy string        // one of the Confirmation constants
+	Confirmations      int64         // depth used by ConfirmationDepth
+	NotifyInclusion    bool          // also notify when a transaction is first included
+	TraceInternal      bool          // trace blocks for internal native transfers, needs the debug namespace
+}
+
+// RPCURLs returns every configured endpoint, WebSocket ones first
+func (n NetworkConfig) RPCURLs() []string {
+	urls := make([]string, 0, len(n.WSURLs)+len(n.HTTPURLs))
+	urls = append(urls, n.WSURLs...)
+	return append(urls, n.HTTPURLs...)
+}
+
+// addRPCURLs sorts urls into the WebSocket or HTTP list by scheme
+func (n *NetworkConfig) addRPCURLs(urls []string) {
+	for _, url := range urls {
+		if IsWebSocketURL(url) {
+			n.WSURLs = append(n.WSURLs, url)
+		} else {
+			n.HTTPURLs = append(n.HTTPURLs, url)
+		}
+	}
+}
+
+// IsWebSocketURL reports whether url uses the ws or wss scheme
+func IsWebSocketURL(url string) bool {
+	lower := strings.ToLower(url)
+	return strings.HasPrefix(lower, "ws://") || strings.HasPrefix(lower, "wss://")
 }
 
 // DatabaseConfig holds database configuration
@@ -42,6 +93,42 @@ type RedisConfig struct {
 	DB       int    `mapstructure:"REDIS_DB"`
 }
 
+// WorkerConfig holds block processing configuration for the worker
+type WorkerConfig struct {
+	MaxCatchUpBlocks int64  `mapstructure:"MAX_CATCHUP_BLOCKS"`  // most blocks backfilled on startup
+	HTTPPort         string `mapstructure:"WORKER_HTTP_PORT"`    // operator status endpoints
+	MetricsPort      string `mapstructure:"WORKER_METRICS_PORT"` // Prometheus metrics listener
+	BlockQueueSize   int    `mapstructure:"BLOCK_QUEUE_SIZE"`    // confirmed blocks buffered per network
+	ProcessorWorkers int    `mapstructure:"PROCESSOR_WORKERS"`   // blocks processed concurrently across networks
+}
+
+// DispatcherConfig holds webhook delivery configuration
+type DispatcherConfig struct {
+	Workers           int           `mapstructure:"WEBHOOK_WORKERS"`             // concurrent deliveries
+	Timeout           time.Duration `mapstructure:"WEBHOOK_TIMEOUT"`             // per request timeout
+	RetryBaseDelay    time.Duration `mapstructure:"WEBHOOK_RETRY_BASE_DELAY"`    // delay before the first retry
+	RetryMaxDelay     time.Duration `mapstructure:"WEBHOOK_RETRY_MAX_DELAY"`     // cap on the exponential delay
+	RetryPollInterval time.Duration `mapstructure:"WEBHOOK_RETRY_POLL_INTERVAL"` // how often due retries are claimed
+	RetryBatchSize    int           `mapstructure:"WEBHOOK_RETRY_BATCH_SIZE"`    // deliveries claimed per poll
+	ConsumerName      string        `mapstructure:"WEBHOOK_CONSUMER_NAME"`       // queue consumer of this dispatcher, defaults to the host name
+	ClaimIdle         time.Duration `mapstructure:"WEBHOOK_CLAIM_IDLE"`          // how long a queued delivery may go unacknowledged before another dispatcher takes it over
+	ClaimInterval     time.Duration `mapstructure:"WEBHOOK_CLAIM_INTERVAL"`      // how often stale queue entries are looked for
+	MetricsPort       string        `mapstructure:"DISPATCHER_METRICS_PORT"`     // Prometheus metrics listener
+}
+
+// StreamConfig holds configuration of the real-time event stream
+type StreamConfig struct {
+	HistorySize    int64    `mapstructure:"STREAM_HISTORY_SIZE"` // recent events kept in Redis for clients resuming a stream
+	AllowedOrigins []string `mapstructure:"-"`                   // browser origins that may open a WebSocket stream, * for any
+}
+
+// TracingConfig holds OpenTelemetry trace export configuration
+type TracingConfig struct {
+	Enabled     bool    `mapstructure:"TRACING_ENABLED"`
+	Endpoint    string  `mapstructure:"OTEL_EXPORTER_OTLP_ENDPOINT"` // OTLP/HTTP collector base URL
+	SampleRatio float64 `mapstructure:"TRACING_SAMPLE_RATIO"`        // share of new traces recorded, 0 to 1
+}
+
 func Load() (*Config, error) {
 	viper.SetDefault("APP_PORT", "8080")
 	viper.SetDefault("LOG_LEVEL", "info")
@@ -51,6 +138,24 @@ func Load() (*Config, error) {
 	viper.SetDefault("REDIS_HOST", "localhost")
 	viper.SetDefault("REDIS_PORT", 6379)
 	viper.SetDefault("REDIS_DB", 0)
+	viper.SetDefault("MAX_CATCHUP_BLOCKS", 1000)
+	viper.SetDefault("WORKER_HTTP_PORT", "9090")
+	viper.SetDefault("WORKER_METRICS_PORT", "9100")
+	viper.SetDefault("BLOCK_QUEUE_SIZE", 50)
+	viper.SetDefault("PROCESSOR_WORKERS", 4)
+	viper.SetDefault("WEBHOOK_WORKERS", 4)
+	viper.SetDefault("WEBHOOK_TIMEOUT", "10s")
+	viper.SetDefault("WEBHOOK_RETRY_BASE_DELAY", "30s")
+	viper.SetDefault("WEBHOOK_RETRY_MAX_DELAY", "1h")
+	viper.SetDefault("WEBHOOK_RETRY_POLL_INTERVAL", "5s")
+	viper.SetDefault("WEBHOOK_RETRY_BATCH_SIZE", 50)
+	viper.SetDefault("WEBHOOK_CLAIM_IDLE", "2m")
+	viper.SetDefault("WEBHOOK_CLAIM_INTERVAL", "30s")
+	viper.SetDefault("DISPATCHER_METRICS_PORT", "9101")
+	viper.SetDefault("STREAM_HISTORY_SIZE", 10000)
+	viper.SetDefault("TRACING_ENABLED", false)
+	viper.SetDefault("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4318")
+	viper.SetDefault("TRACING_SAMPLE_RATIO", 1.0)
 
 	viper.SetConfigFile(".env")
 	viper.AutomaticEnv()
@@ -63,6 +168,7 @@ func Load() (*Config, error) {
 	if err := viper.Unmarshal(&config); err != nil {
 		return nil, fmt.Errorf("error unmarshalling config: %w", err)
 	}
+	config.Stream.AllowedOrigins = splitList(viper.GetString("STREAM_ALLOWED_ORIGINS"))
 
 	if err := validateConfig(&config); err != nil {
 		return nil, err
@@ -71,20 +177,58 @@ func Load() (*Config, error) {
 	// Setup predefined testnet networks
 	config.Networks = getTestnetNetworks()
 
-	// Override RPC URLs from environment if provided
+	// Load RPC URLs from environment. RPC_<NETWORK> takes a comma separated list
+	// of any scheme; RPC_<NETWORK>_WS and RPC_<NETWORK>_HTTP add dedicated ones.
 	for name, network := range config.Networks {
 		key := fmt.Sprintf("RPC_%s", strings.ToUpper(strings.ReplaceAll(name, "-", "_")))
-		if url := viper.GetString(key); url != "" {
-			network.RPC = url
-			config.Networks[name] = network
+		network.addRPCURLs(splitList(viper.GetString(key)))
+		network.addRPCURLs(splitList(viper.GetString(key + "_WS")))
+		network.addRPCURLs(splitList(viper.GetString(key + "_HTTP")))
+
+		// HEAD_SOURCE_<NETWORK> and POLL_INTERVAL_<NETWORK> override how new heads are followed
+		suffix := strings.TrimPrefix(key, "RPC_")
+		if source := viper.GetString("HEAD_SOURCE_" + suffix); source != "" {
+			network.HeadSource = strings.ToLower(source)
 		}
+		if interval := viper.GetString("POLL_INTERVAL_" + suffix); interval != "" {
+			d, err := time.ParseDuration(interval)
+			if err != nil {
+				return nil, fmt.Errorf("invalid POLL_INTERVAL_%s: %w", suffix, err)
+			}
+			network.PollInterval = d
+		}
+
+		// CONFIRMATION_POLICY_<NETWORK>, CONFIRMATIONS_<NETWORK> and NOTIFY_INCLUSION_<NETWORK>
+		// override when transactions are reported
+		if policy := viper.GetString("CONFIRMATION_POLICY_" + suffix); policy != "" {
+			network.ConfirmationPolicy = strings.ToLower(policy)
+		}
+		if viper.IsSet("CONFIRMATIONS_" + suffix) {
+			network.Confirmations = viper.GetInt64("CONFIRMATIONS_" + suffix)
+		}
+		if viper.IsSet("NOTIFY_INCLUSION_" + suffix) {
+			network.NotifyInclusion = viper.GetBool("NOTIFY_INCLUSION_" + suffix)
+		}
+
+		// TRACE_INTERNAL_<NETWORK> enables debug_traceBlockByNumber for internal transfers
+		if viper.IsSet("TRACE_INTERNAL_" + suffix) {
+			network.TraceInternal = viper.GetBool("TRACE_INTERNAL_" + suffix)
+		}
+
+		config.Networks[name] = network
 	}
 
-	// Validate that all networks have RPC URLs
+	// Validate that all networks have RPC URLs and a usable head source
 	for name, network := range config.Networks {
-		if network.RPC == "" {
+		if len(network.RPCURLs()) == 0 {
 			return nil, fmt.Errorf("RPC URL for network %s is not set", name)
 		}
+		if err := validateHeadSource(network); err != nil {
+			return nil, err
+		}
+		if err := validateConfirmationPolicy(network); err != nil {
+			return nil, err
+		}
 	}
 
 	return &config, nil
@@ -94,23 +238,74 @@ func Load() (*Config, error) {
 func getTestnetNetworks() map[string]NetworkConfig {
 	return map[string]NetworkConfig{
 		"ethereum-sepolia": {
-			Name:    "ethereum-sepolia",
-			ChainID: 11155111,
-			RPC:     "", // will be set from env
+			Name:               "ethereum-sepolia",
+			ChainID:            11155111,
+			HeadSource:         HeadSourceAuto,
+			PollInterval:       12 * time.Second,
+			ConfirmationPolicy: ConfirmationSafe,
+			Confirmations:      5,
 		},
 		"base-sepolia": {
-			Name:    "base-sepolia",
-			ChainID: 84532,
-			RPC:     "", // will be set from env
+			Name:               "base-sepolia",
+			ChainID:            84532,
+			HeadSource:         HeadSourceAuto,
+			PollInterval:       2 * time.Second,
+			ConfirmationPolicy: ConfirmationDepth,
+			Confirmations:      10,
 		},
 		"arbitrum-sepolia": {
-			Name:    "arbitrum-sepolia",
-			ChainID: 421614,
-			RPC:     "", // will be set from env
+			Name:               "arbitrum-sepolia",
+			ChainID:            421614,
+			HeadSource:         HeadSourceAuto,
+			PollInterval:       time.Second,
+			ConfirmationPolicy: ConfirmationDepth,
+			Confirmations:      20,
 		},
 	}
 }
 
+// splitList splits a comma separated value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// validateHeadSource checks that a network's head source can be used with its RPC URLs
+func validateHeadSource(network NetworkConfig) error {
+	switch network.HeadSource {
+	case HeadSourceAuto, HeadSourcePoll:
+	case HeadSourceSubscribe:
+		if len(network.WSURLs) == 0 {
+			return fmt.Errorf("head source %q for network %s requires a WebSocket RPC URL", network.HeadSource, network.Name)
+		}
+	default:
+		return fmt.Errorf("unknown head source %q for network %s", network.HeadSource, network.Name)
+	}
+	if network.PollInterval <= 0 {
+		return fmt.Errorf("poll interval for network %s must be positive", network.Name)
+	}
+	return nil
+}
+
+// validateConfirmationPolicy checks a network's confirmation policy
+func validateConfirmationPolicy(network NetworkConfig) error {
+	switch network.ConfirmationPolicy {
+	case ConfirmationDepth:
+		if network.Confirmations < 0 {
+			return fmt.Errorf("confirmations for network %s must not be negative", network.Name)
+		}
+	case ConfirmationSafe, ConfirmationFinalized:
+	default:
+		return fmt.Errorf("unknown confirmation policy %q for network %s", network.ConfirmationPolicy, network.Name)
+	}
+	return nil
+}
+
 // validateConfig validates the loaded configuration
 func validateConfig(cfg *Config) error {
 	if cfg.AppPort == "" {
@@ -122,5 +317,43 @@ func validateConfig(cfg *Config) error {
 	if cfg.DB.Host == "" {
 		return fmt.Errorf("DB_HOST is required")
 	}
+	if cfg.Dispatcher.Workers <= 0 {
+		return fmt.Errorf("WEBHOOK_WORKERS must be positive")
+	}
+	if cfg.Dispatcher.RetryBaseDelay <= 0 || cfg.Dispatcher.RetryMaxDelay < cfg.Dispatcher.RetryBaseDelay {
+		return fmt.Errorf("WEBHOOK_RETRY_MAX_DELAY must be at least WEBHOOK_RETRY_BASE_DELAY, which must be positive")
+	}
+	if cfg.Dispatcher.RetryPollInterval <= 0 || cfg.Dispatcher.RetryBatchSize <= 0 {
+		return fmt.Errorf("WEBHOOK_RETRY_POLL_INTERVAL and WEBHOOK_RETRY_BATCH_SIZE must be positive")
+	}
+	if cfg.Dispatcher.ClaimInterval <= 0 || cfg.Dispatcher.ClaimIdle <= cfg.Dispatcher.Timeout {
+		return fmt.Errorf("WEBHOOK_CLAIM_INTERVAL must be positive and WEBHOOK_CLAIM_IDLE longer than WEBHOOK_TIMEOUT")
+	}
+	if cfg.Worker.HTTPPort == "" || cfg.Worker.MetricsPort == "" {
+		return fmt.Errorf("WORKER_HTTP_PORT and WORKER_METRICS_PORT are required")
+	}
+	if cfg.Dispatcher.MetricsPort == "" {
+		return fmt.Errorf("DISPATCHER_METRICS_PORT is required")
+	}
+	if cfg.Tracing.Enabled && cfg.Tracing.Endpoint == "" {
+		return fmt.Errorf("OTEL_EXPORTER_OTLP_ENDPOINT is required when tracing is enabled")
+	}
+	if cfg.Tracing.SampleRatio < 0 || cfg.Tracing.SampleRatio > 1 {
+		return fmt.Errorf("TRACING_SAMPLE_RATIO must be between 0 and 1")
+	}
+	if cfg.Worker.BlockQueueSize <= 0 || cfg.Worker.ProcessorWorkers <= 0 {
+		return fmt.Errorf("BLOCK_QUEUE_SIZE and PROCESSOR_WORKERS must be positive")
+	}
+	if cfg.Worker.MaxCatchUpBlocks < 0 {
+		return fmt.Errorf("MAX_CATCHUP_BLOCKS must not be negative")
+	}
+	for _, origin := range cfg.Stream.AllowedOrigins {
+		if origin == "*" {
+			continue
+		}
+		if u, err := url.Parse(origin); err != nil || u.Scheme == "" || u.Host == "" || strings.TrimSuffix(u.Path, "/") != "" {
+			return fmt.Errorf("STREAM_ALLOWED_ORIGINS entry %q must be an origin like https://app.example.com", origin)
+		}
+	}
 	return nil
 }
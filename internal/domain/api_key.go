@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKey authenticates requests on behalf of a user. Only the SHA-256 hash of
+// the key is stored; the prefix identifies it in listings.
+type APIKey struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	UserID     uuid.UUID  `json:"user_id" db:"user_id"`
+	Name       string     `json:"name" db:"name"`
+	KeyPrefix  string     `json:"key_prefix" db:"key_prefix"`
+	KeyHash    string     `json:"-" db:"key_hash"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// BackfillJob records how far a historical backfill over a block range got.
+// Blocks below NextBlock are fully processed.
+type BackfillJob struct {
+	ID                 string     `json:"id" db:"id"`
+	ChainID            int64      `json:"chain_id" db:"chain_id"`
+	Network            string     `json:"network" db:"network"`
+	FromBlock          int64      `json:"from_block" db:"from_block"`
+	ToBlock            int64      `json:"to_block" db:"to_block"`
+	NextBlock          int64      `json:"next_block" db:"next_block"`
+	AddressCount       int        `json:"address_count" db:"address_count"`
+	StoredTransactions int64      `json:"stored_transactions" db:"stored_transactions"`
+	CompletedAt        *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// BlockCursor records the last block the worker fully processed for a network
+type BlockCursor struct {
+	ChainID     int64     `json:"chain_id" db:"chain_id"`
+	Network     string    `json:"network" db:"network"`
+	BlockNumber int64     `json:"block_number" db:"block_number"`
+	BlockHash   string    `json:"block_hash" db:"block_hash"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// Stream event types. Transaction events mirror the webhook events; token
+// transfer events repeat each matched transfer on its own for clients that
+// only follow tokens.
+const (
+	StreamEventTransactionIncluded    = string(WebhookEventTransactionIncluded)
+	StreamEventTransactionConfirmed   = string(WebhookEventTransactionConfirmed)
+	StreamEventTransactionReverted    = string(WebhookEventTransactionReverted)
+	StreamEventTokenTransferIncluded  = "token_transfer.included"
+	StreamEventTokenTransferConfirmed = "token_transfer.confirmed"
+	StreamEventBlockReorged           = "block.reorged"
+)
+
+// StreamEvent is pushed to stream clients as matched data is persisted
+type StreamEvent struct {
+	ID            string         `json:"id"` // Redis stream entry ID, increasing; pass it back to resume
+	Type          string         `json:"type"`
+	ChainID       int64          `json:"chain_id"`
+	Transaction   *Transaction   `json:"transaction,omitempty"`
+	TokenTransfer *TokenTransfer `json:"token_transfer,omitempty"`
+	Reorg         *ReorgNotice   `json:"reorg,omitempty"`
+	Timestamp     time.Time      `json:"timestamp"`
+}
+
+// ReorgNotice describes a block that left the canonical chain
+type ReorgNotice struct {
+	BlockNumber int64  `json:"block_number"`
+	BlockHash   string `json:"block_hash"`
+}
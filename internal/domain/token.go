@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"math/big"
+	"strings"
+	"time"
+)
+
+// Token holds the metadata of a token contract. Fields are nil when the
+// contract does not implement the corresponding getter.
+type Token struct {
+	ChainID   int64     `json:"chain_id" db:"chain_id"`
+	Address   string    `json:"address" db:"address"`
+	Decimals  *int      `json:"decimals,omitempty" db:"decimals"`
+	Symbol    *string   `json:"symbol,omitempty" db:"symbol"`
+	Name      *string   `json:"name,omitempty" db:"name"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// FormatUnits renders a raw token amount as a decimal string, e.g. 1500000
+// with 6 decimals becomes "1.5"
+func FormatUnits(value *big.Int, decimals int) string {
+	if value == nil {
+		return "0"
+	}
+	if decimals <= 0 {
+		return value.String()
+	}
+
+	abs := new(big.Int).Abs(value)
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	whole, frac := new(big.Int).QuoRem(abs, unit, new(big.Int))
+
+	result := whole.String()
+	if frac.Sign() != 0 {
+		fraction := strings.TrimRight(frac.String(), "0")
+		result += "." + strings.Repeat("0", decimals-len(frac.String())) + fraction
+	}
+	if value.Sign() < 0 {
+		result = "-" + result
+	}
+	return result
+}
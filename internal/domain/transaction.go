@@ -5,59 +5,104 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // Transaction represents a blockchain transaction
 type Transaction struct {
-	ID               uuid.UUID    `json:"id" db:"id"`
-	Hash             string       `json:"hash" db:"hash"`
-	BlockNumber      int64        `json:"block_number" db:"block_number"`
-	BlockHash        string       `json:"block_hash" db:"block_hash"`
-	TransactionIndex int          `json:"transaction_index" db:"transaction_index"`
-	ChainID          int64        `json:"chain_id" db:"chain_id"`
-	FromAddress      string       `json:"from_address" db:"from_address"`
-	ToAddress        *string      `json:"to_address,omitempty" db:"to_address"`
-	Value            *big.Int     `json:"value" db:"value"` // Wei amount for ETH transfers
-	GasUsed          *int64       `json:"gas_used,omitempty" db:"gas_used"`
-	GasPrice         *big.Int     `json:"gas_price,omitempty" db:"gas_price"`
-	TxType           int          `json:"tx_type" db:"tx_type"`
-	Status           int          `json:"status" db:"status"` // 1=success, 0=failed
-	BlockTimestamp   time.Time    `json:"block_timestamp" db:"block_timestamp"`
-	CreatedAt        time.Time    `json:"created_at" db:"created_at"`
-	TokenTransfers   []TokenTransfer `json:"token_transfers,omitempty" db:"-"`
+	ID                uuid.UUID          `json:"id" db:"id"`
+	Hash              string             `json:"hash" db:"hash"`
+	BlockNumber       int64              `json:"block_number" db:"block_number"`
+	BlockHash         string             `json:"block_hash" db:"block_hash"`
+	TransactionIndex  int                `json:"transaction_index" db:"transaction_index"`
+	ChainID           int64              `json:"chain_id" db:"chain_id"`
+	FromAddress       string             `json:"from_address" db:"from_address"`
+	ToAddress         *string            `json:"to_address,omitempty" db:"to_address"`
+	Value             *big.Int           `json:"value" db:"value" swaggertype:"integer"` // Wei amount for ETH transfers
+	GasUsed           *int64             `json:"gas_used,omitempty" db:"gas_used"`
+	GasPrice          *big.Int           `json:"gas_price,omitempty" db:"gas_price" swaggertype:"integer"`
+	TxType            int                `json:"tx_type" db:"tx_type"`
+	Status            int                `json:"status" db:"status"` // 1=success, 0=failed
+	BlockTimestamp    time.Time          `json:"block_timestamp" db:"block_timestamp"`
+	Removed           bool               `json:"removed" db:"removed"` // true once the block left the canonical chain
+	CreatedAt         time.Time          `json:"created_at" db:"created_at"`
+	TokenTransfers    []TokenTransfer    `json:"token_transfers,omitempty" db:"-"`
+	InternalTransfers []InternalTransfer `json:"internal_transfers,omitempty" db:"-"`
 }
 
-// TokenTransfer represents an ERC-20 token transfer within a transaction
+// TokenStandard identifies the token interface a transfer was emitted by
+type TokenStandard string
+
+const (
+	TokenStandardERC20   TokenStandard = "erc20"
+	TokenStandardERC721  TokenStandard = "erc721"
+	TokenStandardERC1155 TokenStandard = "erc1155"
+)
+
+// TokenTransfer represents an ERC-20, ERC-721 or ERC-1155 token transfer within a transaction
 type TokenTransfer struct {
+	ID              uuid.UUID     `json:"id" db:"id"`
+	TransactionID   uuid.UUID     `json:"transaction_id" db:"transaction_id"`
+	LogIndex        int           `json:"log_index" db:"log_index"`
+	BatchIndex      int           `json:"batch_index" db:"batch_index"` // position within an ERC-1155 TransferBatch, 0 otherwise
+	Standard        TokenStandard `json:"standard" db:"standard"`
+	TokenAddress    string        `json:"token_address" db:"token_address"`
+	OperatorAddress *string       `json:"operator_address,omitempty" db:"operator_address"` // ERC-1155 only
+	FromAddress     string        `json:"from_address" db:"from_address"`
+	ToAddress       string        `json:"to_address" db:"to_address"`
+	TokenID         *big.Int      `json:"token_id,omitempty" db:"token_id" swaggertype:"integer"` // ERC-721 and ERC-1155 only
+	Value           *big.Int      `json:"value" db:"value" swaggertype:"integer"`                 // Raw token amount, 1 for ERC-721
+	TokenDecimals   *int          `json:"token_decimals,omitempty" db:"token_decimals"`
+	TokenSymbol     *string       `json:"token_symbol,omitempty" db:"token_symbol"`
+	TokenName       *string       `json:"token_name,omitempty" db:"token_name"`
+	FormattedValue  *string       `json:"formatted_value,omitempty" db:"-"` // Value scaled by TokenDecimals
+	Removed         bool          `json:"removed" db:"removed"`
+	CreatedAt       time.Time     `json:"created_at" db:"created_at"`
+}
+
+// InternalTransfer represents native value moved by a call inside a transaction,
+// e.g. a contract paying out ETH, as reported by the callTracer
+type InternalTransfer struct {
 	ID            uuid.UUID `json:"id" db:"id"`
 	TransactionID uuid.UUID `json:"transaction_id" db:"transaction_id"`
-	LogIndex      int       `json:"log_index" db:"log_index"`
-	TokenAddress  string    `json:"token_address" db:"token_address"`
+	TraceIndex    int       `json:"trace_index" db:"trace_index"` // order within the transaction's call tree
+	CallType      string    `json:"call_type" db:"call_type"`     // CALL, CREATE, CREATE2, SELFDESTRUCT, ...
 	FromAddress   string    `json:"from_address" db:"from_address"`
 	ToAddress     string    `json:"to_address" db:"to_address"`
-	Value         *big.Int  `json:"value" db:"value"` // Raw token amount
-	TokenDecimals *int      `json:"token_decimals,omitempty" db:"token_decimals"`
-	TokenSymbol   *string   `json:"token_symbol,omitempty" db:"token_symbol"`
-	TokenName     *string   `json:"token_name,omitempty" db:"token_name"`
+	Value         *big.Int  `json:"value" db:"value" swaggertype:"integer"` // Wei amount
+	Depth         int       `json:"depth" db:"depth"`                       // 1 for calls made directly by the transaction
+	Removed       bool      `json:"removed" db:"removed"`
 	CreatedAt     time.Time `json:"created_at" db:"created_at"`
 }
 
 // WebhookDelivery represents a webhook delivery attempt
 type WebhookDelivery struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	WebhookID      uuid.UUID  `json:"webhook_id" db:"webhook_id"`
+	TransactionID  uuid.UUID  `json:"transaction_id" db:"transaction_id"`
+	Payload        string     `json:"payload" db:"payload"` // JSON string
+	Status         string     `json:"status" db:"status"`
+	HTTPStatusCode *int       `json:"http_status_code,omitempty" db:"http_status_code"`
+	ResponseBody   *string    `json:"response_body,omitempty" db:"response_body"`
+	ErrorMessage   *string    `json:"error_message,omitempty" db:"error_message"`
+	RetryCount     int        `json:"retry_count" db:"retry_count"`
+	MaxRetries     int        `json:"max_retries" db:"max_retries"`
+	NextRetryAt    *time.Time `json:"next_retry_at,omitempty" db:"next_retry_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty" db:"delivered_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+	TraceParent    *string    `json:"-" db:"trace_parent"` // trace of the block that caused the delivery
+}
+
+// WebhookDeliveryAttempt records one HTTP attempt of a delivery
+type WebhookDeliveryAttempt struct {
 	ID             uuid.UUID `json:"id" db:"id"`
-	WebhookID      uuid.UUID `json:"webhook_id" db:"webhook_id"`
-	TransactionID  uuid.UUID `json:"transaction_id" db:"transaction_id"`
-	Payload        string    `json:"payload" db:"payload"` // JSON string
-	Status         string    `json:"status" db:"status"`
+	DeliveryID     uuid.UUID `json:"delivery_id" db:"delivery_id"`
 	HTTPStatusCode *int      `json:"http_status_code,omitempty" db:"http_status_code"`
 	ResponseBody   *string   `json:"response_body,omitempty" db:"response_body"`
 	ErrorMessage   *string   `json:"error_message,omitempty" db:"error_message"`
-	RetryCount     int       `json:"retry_count" db:"retry_count"`
-	MaxRetries     int       `json:"max_retries" db:"max_retries"`
-	NextRetryAt    *time.Time `json:"next_retry_at,omitempty" db:"next_retry_at"`
-	DeliveredAt    *time.Time `json:"delivered_at,omitempty" db:"delivered_at"`
+	DurationMs     int64     `json:"duration_ms" db:"duration_ms"`
 	CreatedAt      time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
 }
 
 // WebhookDeliveryStatus represents the status of webhook delivery
@@ -72,10 +117,11 @@ const (
 
 // WatchedAddress represents an address being monitored
 type WatchedAddress struct {
-	Address    string `json:"address"`
-	ChainID    int64  `json:"chain_id"`
-	IsActive   bool   `json:"is_active"`
-	WebhookID  uuid.UUID `json:"webhook_id"`
-	WebhookURL string `json:"webhook_url"`
+	Address    string         `json:"address" db:"address"`
+	ChainID    int64          `json:"chain_id" db:"chain_id"`
+	IsActive   bool           `json:"is_active" db:"is_active"`
+	WebhookID  uuid.UUID      `json:"webhook_id" db:"webhook_id"`
+	WebhookURL string         `json:"webhook_url" db:"webhook_url"`
+	Events     pq.StringArray `json:"events" db:"events"` // filter of the webhook
+	UserID     *uuid.UUID     `json:"user_id,omitempty" db:"user_id"`
 }
-
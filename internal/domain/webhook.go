@@ -1,16 +1,75 @@
 package domain
 
 import (
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type Webhook struct {
-	ID        uuid.UUID `json:"id" db:"id"`
-	AddressID uuid.UUID `json:"address_id" db:"address_id"`
-	URL       string    `json:"url" db:"url"`
-	Secret    string    `json:"-" db:"secret"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	ID        uuid.UUID      `json:"id" db:"id"`
+	AddressID uuid.UUID      `json:"address_id" db:"address_id"`
+	URL       string         `json:"url" db:"url"`
+	Secret    string         `json:"-" db:"secret"`
+	IsActive  bool           `json:"is_active" db:"is_active"`
+	Events    pq.StringArray `json:"events" db:"events"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// WebhookEventFilter selects which activity of its address a webhook is
+// notified about. Direction filters and kind filters combine: a webhook with
+// incoming and token only hears about tokens received.
+type WebhookEventFilter string
+
+const (
+	WebhookFilterIncoming WebhookEventFilter = "incoming" // the address receives value or is called
+	WebhookFilterOutgoing WebhookEventFilter = "outgoing" // the address sends
+	WebhookFilterToken    WebhookEventFilter = "token"    // ERC-20, ERC-721 and ERC-1155 transfers
+	WebhookFilterNative   WebhookEventFilter = "native"   // the transaction itself and internal transfers
+)
+
+// AllWebhookEventFilters is the filter of a webhook that wants everything
+var AllWebhookEventFilters = []string{
+	string(WebhookFilterIncoming),
+	string(WebhookFilterOutgoing),
+	string(WebhookFilterToken),
+	string(WebhookFilterNative),
+}
+
+// AcceptsActivity reports whether a webhook with the given filter wants
+// activity in direction of kind. A filter without any direction, or without
+// any kind, does not restrict that dimension.
+func AcceptsActivity(events []string, direction, kind WebhookEventFilter) bool {
+	return acceptsDimension(events, direction, WebhookFilterIncoming, WebhookFilterOutgoing) &&
+		acceptsDimension(events, kind, WebhookFilterToken, WebhookFilterNative)
+}
+
+func acceptsDimension(events []string, value WebhookEventFilter, options ...WebhookEventFilter) bool {
+	restricted := false
+	for _, option := range options {
+		if slices.Contains(events, string(option)) {
+			restricted = true
+		}
+	}
+	return !restricted || slices.Contains(events, string(value))
+}
+
+// WebhookEventType identifies what a webhook notification is about
+type WebhookEventType string
+
+const (
+	WebhookEventTransactionIncluded  WebhookEventType = "transaction.included"
+	WebhookEventTransactionConfirmed WebhookEventType = "transaction.confirmed"
+	WebhookEventTransactionReverted  WebhookEventType = "transaction.reverted"
+)
+
+// WebhookPayload is the JSON body sent to webhook subscribers
+type WebhookPayload struct {
+	Event       WebhookEventType `json:"event"`
+	ChainID     int64            `json:"chain_id"`
+	Transaction *Transaction     `json:"transaction"`
+	Timestamp   time.Time        `json:"timestamp"`
 }
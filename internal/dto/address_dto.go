@@ -11,6 +11,13 @@ type RegisterAddressRequest struct {
 	Description *string `json:"description,omitempty" validate:"omitempty,max=255"`
 }
 
+// UpdateAddressRequest changes only the fields that are present
+type UpdateAddressRequest struct {
+	Label       *string `json:"label,omitempty" validate:"omitempty,max=100"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=255"`
+	IsActive    *bool   `json:"is_active,omitempty"`
+}
+
 type AddressResponse struct {
 	ID          string    `json:"id"`
 	Address     string    `json:"address"`
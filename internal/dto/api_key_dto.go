@@ -0,0 +1,18 @@
+package dto
+
+import "time"
+
+type CreateAPIKeyRequest struct {
+	Name string `json:"name" validate:"required,max=255"`
+}
+
+type APIKeyResponse struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	Name       string     `json:"name"`
+	KeyPrefix  string     `json:"key_prefix"`
+	Key        string     `json:"key,omitempty"` // only returned when the key is created
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
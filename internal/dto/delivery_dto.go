@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"evm-tx-watcher/internal/domain"
+)
+
+// ListDeliveriesRequest holds the query parameters of a webhook's delivery history
+type ListDeliveriesRequest struct {
+	Status   []string `query:"status" validate:"omitempty,dive,oneof=pending delivered failed max_retries_exceeded"`
+	FromTime *string  `query:"from_time" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	ToTime   *string  `query:"to_time" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Cursor   string   `query:"cursor"`
+	Limit    int      `query:"limit" validate:"omitempty,min=1,max=200"`
+}
+
+// RedeliverFailedRequest selects the failed deliveries to send again by creation time
+type RedeliverFailedRequest struct {
+	FromTime string `json:"from_time" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+	ToTime   string `json:"to_time" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+type DeliveryResponse struct {
+	ID             string                           `json:"id"`
+	WebhookID      string                           `json:"webhook_id"`
+	TransactionID  string                           `json:"transaction_id"`
+	Event          string                           `json:"event,omitempty"`
+	Status         string                           `json:"status"`
+	HTTPStatusCode *int                             `json:"http_status_code,omitempty"`
+	ResponseBody   *string                          `json:"response_body,omitempty"`
+	ErrorMessage   *string                          `json:"error_message,omitempty"`
+	RetryCount     int                              `json:"retry_count"`
+	MaxRetries     int                              `json:"max_retries"`
+	NextRetryAt    *time.Time                       `json:"next_retry_at,omitempty"`
+	DeliveredAt    *time.Time                       `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time                        `json:"created_at"`
+	UpdatedAt      time.Time                        `json:"updated_at"`
+	Payload        json.RawMessage                  `json:"payload,omitempty" swaggertype:"object"` // only on single deliveries
+	Attempts       []*domain.WebhookDeliveryAttempt `json:"attempts,omitempty"`                     // only on single deliveries
+}
+
+type DeliveryListResponse struct {
+	Deliveries []*DeliveryResponse `json:"deliveries"`
+	NextCursor *string             `json:"next_cursor,omitempty"` // absent on the last page
+}
+
+type RedeliveryResponse struct {
+	Count       int      `json:"count"`
+	DeliveryIDs []string `json:"delivery_ids"`
+}
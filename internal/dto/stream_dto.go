@@ -0,0 +1,10 @@
+package dto
+
+// StreamRequest holds the query parameters of an event stream. Lists are comma
+// separated or repeated; empty lists do not filter.
+type StreamRequest struct {
+	ChainIDs    []string `query:"chain_id" validate:"omitempty,dive,number"`
+	Addresses   []string `query:"address" validate:"omitempty,dive,eth_addr"`
+	Events      []string `query:"events" validate:"omitempty,dive,oneof=transaction token_transfer block transaction.included transaction.confirmed transaction.reverted token_transfer.included token_transfer.confirmed block.reorged"`
+	LastEventID string   `query:"last_event_id"`
+}
@@ -0,0 +1,23 @@
+package dto
+
+import "evm-tx-watcher/internal/domain"
+
+// ListTransactionsRequest holds the query parameters of the transaction list
+type ListTransactionsRequest struct {
+	ChainID        *int64  `query:"chain_id" validate:"omitempty,gt=0"`
+	Address        *string `query:"address" validate:"omitempty,eth_addr"`
+	FromBlock      *int64  `query:"from_block" validate:"omitempty,gte=0"`
+	ToBlock        *int64  `query:"to_block" validate:"omitempty,gte=0"`
+	FromTime       *string `query:"from_time" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	ToTime         *string `query:"to_time" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Status         *int    `query:"status" validate:"omitempty,oneof=0 1"`
+	TokenAddress   *string `query:"token_address" validate:"omitempty,eth_addr"`
+	IncludeRemoved bool    `query:"include_removed"`
+	Cursor         string  `query:"cursor"`
+	Limit          int     `query:"limit" validate:"omitempty,min=1,max=200"`
+}
+
+type TransactionListResponse struct {
+	Transactions []*domain.Transaction `json:"transactions"`
+	NextCursor   *string               `json:"next_cursor,omitempty"` // absent on the last page
+}
@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+type CreateWebhookRequest struct {
+	URL      string   `json:"url" validate:"required,url"`
+	Secret   string   `json:"secret" validate:"required,min=10"`
+	Events   []string `json:"events,omitempty" validate:"omitempty,dive,oneof=incoming outgoing token native"`
+	IsActive *bool    `json:"is_active,omitempty"`
+}
+
+// UpdateWebhookRequest changes only the fields that are present
+type UpdateWebhookRequest struct {
+	URL      *string  `json:"url,omitempty" validate:"omitempty,url"`
+	Secret   *string  `json:"secret,omitempty" validate:"omitempty,min=10"`
+	Events   []string `json:"events,omitempty" validate:"omitempty,dive,oneof=incoming outgoing token native"`
+	IsActive *bool    `json:"is_active,omitempty"`
+}
+
+type WebhookResponse struct {
+	ID        string    `json:"id"`
+	AddressID string    `json:"address_id"`
+	URL       string    `json:"url"`
+	IsActive  bool      `json:"is_active"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,96 @@
+// Package health checks the dependencies a process needs before it can serve
+package health
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"evm-tx-watcher/db"
+	"evm-tx-watcher/internal/cache"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// checkTimeout bounds each dependency check so a hanging dependency fails the probe instead of stalling it
+const checkTimeout = 2 * time.Second
+
+// Check statuses
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// Check is the outcome of one dependency check
+type Check struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Migration is the applied database schema version
+type Migration struct {
+	Version uint `json:"version"`
+	Dirty   bool `json:"dirty"` // the last migration failed halfway and needs manual repair
+}
+
+// Readiness is the outcome of every dependency check
+type Readiness struct {
+	Ready     bool             `json:"ready"`
+	Checks    map[string]Check `json:"checks"`
+	Migration *Migration       `json:"migration,omitempty"`
+}
+
+// Checker checks Postgres, Redis and the schema migrations
+type Checker struct {
+	db    *sqlx.DB
+	redis *cache.RedisClient
+}
+
+func NewChecker(db *sqlx.DB, redis *cache.RedisClient) *Checker {
+	return &Checker{db: db, redis: redis}
+}
+
+// Ready pings Postgres and Redis and reads the schema version. It is ready
+// only when every check is up.
+func (c *Checker) Ready(ctx context.Context) Readiness {
+	readiness := Readiness{Ready: true, Checks: make(map[string]Check)}
+	record := func(name string, fn func(ctx context.Context) error) {
+		check := run(ctx, fn)
+		if check.Status != StatusUp {
+			readiness.Ready = false
+		}
+		readiness.Checks[name] = check
+	}
+
+	record("postgres", c.db.PingContext)
+	record("redis", c.redis.Ping)
+	record("migrations", func(ctx context.Context) error {
+		version, dirty, err := db.MigrationVersion(ctx, c.db)
+		if err != nil {
+			return err
+		}
+		readiness.Migration = &Migration{Version: version, Dirty: dirty}
+		if dirty {
+			return fmt.Errorf("migration %d is dirty", version)
+		}
+		return nil
+	})
+
+	return readiness
+}
+
+// run performs one check within checkTimeout
+func run(ctx context.Context, fn func(ctx context.Context) error) Check {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	started := time.Now()
+	err := fn(ctx)
+	check := Check{Status: StatusUp, LatencyMs: float64(time.Since(started).Microseconds()) / 1000}
+	if err != nil {
+		check.Status = StatusDown
+		check.Error = err.Error()
+	}
+	return check
+}
@@ -3,12 +3,15 @@ package handler
 import (
 	"evm-tx-watcher/internal/dto"
 	"evm-tx-watcher/internal/errors"
+	"evm-tx-watcher/internal/http/middleware"
 	"evm-tx-watcher/internal/http/response"
 	"evm-tx-watcher/internal/service"
 	"evm-tx-watcher/internal/util"
 	"evm-tx-watcher/internal/validator"
+	"fmt"
 	"net/http"
 
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
@@ -39,6 +42,8 @@ func NewAddressHandler(
 // @Param        payload body dto.RegisterAddressRequest true "Register Request"
 // @Success      201 {object} dto.AddressResponse
 // @Failure      400 {object} dto.BaseResponse
+// @Failure      401 {object} dto.BaseResponse
+// @Security     ApiKeyAuth
 // @Router       /addresses [post]
 func (h *AddressHandler) Register(c echo.Context) error {
 	var request dto.RegisterAddressRequest
@@ -55,7 +60,7 @@ func (h *AddressHandler) Register(c echo.Context) error {
 
 	}
 
-	createdAddress, err := h.addressService.Register(c.Request().Context(), &request)
+	createdAddress, err := h.addressService.Register(c.Request().Context(), middleware.UserID(c), &request)
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to register address")
 		return response.SendAppError(c, err)
@@ -71,9 +76,11 @@ func (h *AddressHandler) Register(c echo.Context) error {
 // @Produce      json
 // @Success      200 {array} dto.AddressResponse
 // @Failure      400 {object} dto.BaseResponse
+// @Failure      401 {object} dto.BaseResponse
+// @Security     ApiKeyAuth
 // @Router       /addresses [get]
 func (h *AddressHandler) GetAll(c echo.Context) error {
-	addresses, err := h.addressService.GetAll(c.Request().Context())
+	addresses, err := h.addressService.GetAll(c.Request().Context(), middleware.UserID(c))
 	if err != nil {
 		h.logger.WithError(err).Error("Failed to retrieve addresses")
 		return response.SendAppError(c, err)
@@ -81,3 +88,105 @@ func (h *AddressHandler) GetAll(c echo.Context) error {
 
 	return response.SendSuccess(c, http.StatusOK, "Addresses retrieved successfully", addresses)
 }
+
+// GetByID godoc
+// @Summary      Get a registered address
+// @Description  Retrieve a single address by its ID
+// @Tags         addresses
+// @Produce      json
+// @Param        id path string true "Address ID"
+// @Success      200 {object} dto.AddressResponse
+// @Failure      400 {object} dto.BaseResponse
+// @Failure      404 {object} dto.BaseResponse
+// @Failure      401 {object} dto.BaseResponse
+// @Security     ApiKeyAuth
+// @Router       /addresses/{id} [get]
+func (h *AddressHandler) GetByID(c echo.Context) error {
+	id, appErr := parseID(c, "id", "address")
+	if appErr != nil {
+		return response.SendAppError(c, appErr)
+	}
+
+	address, appErr := h.addressService.GetByID(c.Request().Context(), middleware.UserID(c), id)
+	if appErr != nil {
+		h.logger.WithError(appErr).Error("Failed to retrieve address")
+		return response.SendAppError(c, appErr)
+	}
+
+	return response.SendSuccess(c, http.StatusOK, "Address retrieved successfully", address)
+}
+
+// Update godoc
+// @Summary      Update a registered address
+// @Description  Change the label, description or active state of an address; inactive addresses are not monitored
+// @Tags         addresses
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Address ID"
+// @Param        payload body dto.UpdateAddressRequest true "Update Request"
+// @Success      200 {object} dto.AddressResponse
+// @Failure      400 {object} dto.BaseResponse
+// @Failure      404 {object} dto.BaseResponse
+// @Failure      401 {object} dto.BaseResponse
+// @Security     ApiKeyAuth
+// @Router       /addresses/{id} [patch]
+func (h *AddressHandler) Update(c echo.Context) error {
+	id, appErr := parseID(c, "id", "address")
+	if appErr != nil {
+		return response.SendAppError(c, appErr)
+	}
+
+	var request dto.UpdateAddressRequest
+	if err := c.Bind(&request); err != nil {
+		h.logger.WithError(err).Error("Failed to bind request")
+		return response.SendAppError(c, errors.ValidationError("Invalid JSON format"))
+	}
+
+	if err := h.validator.Validate(request); err != nil {
+		h.logger.WithError(err).Error("Failed to validate request")
+		return response.SendValidationError(c, h.validator, err)
+	}
+
+	address, appErr := h.addressService.Update(c.Request().Context(), middleware.UserID(c), id, &request)
+	if appErr != nil {
+		h.logger.WithError(appErr).Error("Failed to update address")
+		return response.SendAppError(c, appErr)
+	}
+
+	return response.SendSuccess(c, http.StatusOK, "Address updated successfully", address)
+}
+
+// Delete godoc
+// @Summary      Delete a registered address
+// @Description  Stop monitoring an address and remove it together with its webhooks
+// @Tags         addresses
+// @Produce      json
+// @Param        id path string true "Address ID"
+// @Success      200 {object} dto.BaseResponse
+// @Failure      400 {object} dto.BaseResponse
+// @Failure      404 {object} dto.BaseResponse
+// @Failure      401 {object} dto.BaseResponse
+// @Security     ApiKeyAuth
+// @Router       /addresses/{id} [delete]
+func (h *AddressHandler) Delete(c echo.Context) error {
+	id, appErr := parseID(c, "id", "address")
+	if appErr != nil {
+		return response.SendAppError(c, appErr)
+	}
+
+	if appErr := h.addressService.Delete(c.Request().Context(), middleware.UserID(c), id); appErr != nil {
+		h.logger.WithError(appErr).Error("Failed to delete address")
+		return response.SendAppError(c, appErr)
+	}
+
+	return response.SendSuccess(c, http.StatusOK, "Address deleted successfully", nil)
+}
+
+// parseID reads the UUID path parameter name identifying a resource
+func parseID(c echo.Context, name, resource string) (uuid.UUID, *errors.AppError) {
+	id, err := uuid.Parse(c.Param(name))
+	if err != nil {
+		return uuid.Nil, errors.ValidationError(fmt.Sprintf("Invalid %s ID", resource))
+	}
+	return id, nil
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"evm-tx-watcher/internal/dto"
+	"evm-tx-watcher/internal/errors"
+	"evm-tx-watcher/internal/http/middleware"
+	"evm-tx-watcher/internal/http/response"
+	"evm-tx-watcher/internal/service"
+	"evm-tx-watcher/internal/util"
+	"evm-tx-watcher/internal/validator"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type APIKeyHandler struct {
+	apiKeyService service.APIKeyService
+	logger        *util.Logger
+	validator     *validator.Validator
+}
+
+func NewAPIKeyHandler(
+	apiKeyService service.APIKeyService,
+	logger *util.Logger,
+	validator *validator.Validator,
+) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+		logger:        logger,
+		validator:     validator,
+	}
+}
+
+// List godoc
+// @Summary      List the caller's API keys
+// @Description  Keys are identified by their prefix; the full key is only shown when it is created
+// @Tags         api-keys
+// @Produce      json
+// @Success      200 {array} dto.APIKeyResponse
+// @Failure      401 {object} dto.BaseResponse
+// @Security     ApiKeyAuth
+// @Router       /api-keys [get]
+func (h *APIKeyHandler) List(c echo.Context) error {
+	keys, appErr := h.apiKeyService.List(c.Request().Context(), middleware.UserID(c))
+	if appErr != nil {
+		h.logger.WithError(appErr).Error("Failed to retrieve api keys")
+		return response.SendAppError(c, appErr)
+	}
+
+	return response.SendSuccess(c, http.StatusOK, "API keys retrieved successfully", keys)
+}
+
+// Create godoc
+// @Summary      Create an API key
+// @Description  Issue another key for the caller's user, e.g. to rotate keys without downtime
+// @Tags         api-keys
+// @Accept       json
+// @Produce      json
+// @Param        payload body dto.CreateAPIKeyRequest true "API Key Request"
+// @Success      201 {object} dto.APIKeyResponse
+// @Failure      400 {object} dto.BaseResponse
+// @Failure      401 {object} dto.BaseResponse
+// @Security     ApiKeyAuth
+// @Router       /api-keys [post]
+func (h *APIKeyHandler) Create(c echo.Context) error {
+	var request dto.CreateAPIKeyRequest
+	if err := c.Bind(&request); err != nil {
+		h.logger.WithError(err).Error("Failed to bind request")
+		return response.SendAppError(c, errors.ValidationError("Invalid JSON format"))
+	}
+
+	if err := h.validator.Validate(request); err != nil {
+		h.logger.WithError(err).Error("Failed to validate request")
+		return response.SendValidationError(c, h.validator, err)
+	}
+
+	key, appErr := h.apiKeyService.Create(c.Request().Context(), middleware.UserID(c), &request)
+	if appErr != nil {
+		h.logger.WithError(appErr).Error("Failed to create api key")
+		return response.SendAppError(c, appErr)
+	}
+
+	return response.SendSuccess(c, http.StatusCreated, "API key created successfully", key)
+}
+
+// Revoke godoc
+// @Summary      Revoke an API key
+// @Description  The key stops working immediately; revoking the key used for the request is allowed
+// @Tags         api-keys
+// @Produce      json
+// @Param        id path string true "API Key ID"
+// @Success      200 {object} dto.BaseResponse
+// @Failure      400 {object} dto.BaseResponse
+// @Failure      404 {object} dto.BaseResponse
+// @Failure      401 {object} dto.BaseResponse
+// @Security     ApiKeyAuth
+// @Router       /api-keys/{id} [delete]
+func (h *APIKeyHandler) Revoke(c echo.Context) error {
+	id, appErr := parseID(c, "id", "API key")
+	if appErr != nil {
+		return response.SendAppError(c, appErr)
+	}
+
+	if appErr := h.apiKeyService.Revoke(c.Request().Context(), middleware.UserID(c), id); appErr != nil {
+		h.logger.WithError(appErr).Error("Failed to revoke api key")
+		return response.SendAppError(c, appErr)
+	}
+
+	return response.SendSuccess(c, http.StatusOK, "API key revoked successfully", nil)
+}
@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"evm-tx-watcher/internal/dto"
+	"evm-tx-watcher/internal/errors"
+	"evm-tx-watcher/internal/http/middleware"
+	"evm-tx-watcher/internal/http/response"
+	"evm-tx-watcher/internal/service"
+	"evm-tx-watcher/internal/util"
+	"evm-tx-watcher/internal/validator"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type DeliveryHandler struct {
+	deliveryService service.DeliveryService
+	logger          *util.Logger
+	validator       *validator.Validator
+}
+
+func NewDeliveryHandler(
+	deliveryService service.DeliveryService,
+	logger *util.Logger,
+	validator *validator.Validator,
+) *DeliveryHandler {
+	return &DeliveryHandler{
+		deliveryService: deliveryService,
+		logger:          logger,
+		validator:       validator,
+	}
+}
+
+// List godoc
+// @Summary      List the deliveries of a webhook
+// @Description  Newest first, paginated with the opaque next_cursor of the previous page
+// @Tags         deliveries
+// @Produce      json
+// @Param        id path string true "Address ID"
+// @Param        webhookId path string true "Webhook ID"
+// @Param        status query string false "Comma separated statuses: pending, delivered, failed, max_retries_exceeded"
+// @Param        from_time query string false "Earliest creation time, RFC 3339"
+// @Param        to_time query string false "Latest creation time, RFC 3339"
+// @Param        cursor query string false "next_cursor of the previous page"
+// @Param        limit query int false "Page size, 1-200, default 50"
+// @Success      200 {object} dto.DeliveryListResponse
+// @Failure      400 {object} dto.BaseResponse
+// @Failure      404 {object} dto.BaseResponse
+// @Failure      401 {object} dto.BaseResponse
+// @Security     ApiKeyAuth
+// @Router       /addresses/{id}/webhooks/{webhookId}/deliveries [get]
+func (h *DeliveryHandler) List(c echo.Context) error {
+	addressID, webhookID, appErr := parseWebhookPath(c)
+	if appErr != nil {
+		return response.SendAppError(c, appErr)
+	}
+
+	var request dto.ListDeliveriesRequest
+	if err := c.Bind(&request); err != nil {
+		h.logger.WithError(err).Error("Failed to bind request")
+		return response.SendAppError(c, errors.ValidationError("Invalid query parameters"))
+	}
+	request.Status = splitList(request.Status)
+
+	if err := h.validator.Validate(request); err != nil {
+		h.logger.WithError(err).Error("Failed to validate request")
+		return response.SendValidationError(c, h.validator, err)
+	}
+
+	deliveries, appErr := h.deliveryService.List(c.Request().Context(), middleware.UserID(c), addressID, webhookID, &request)
+	if appErr != nil {
+		h.logger.WithError(appErr).Error("Failed to list deliveries")
+		return response.SendAppError(c, appErr)
+	}
+
+	return response.SendSuccess(c, http.StatusOK, "Deliveries retrieved successfully", deliveries)
+}
+
+// Get godoc
+// @Summary      Get a delivery
+// @Description  Retrieve a delivery with its payload and every attempt to send it
+// @Tags         deliveries
+// @Produce      json
+// @Param        id path string true "Address ID"
+// @Param        webhookId path string true "Webhook ID"
+// @Param        deliveryId path string true "Delivery ID"
+// @Success      200 {object} dto.DeliveryResponse
+// @Failure      400 {object} dto.BaseResponse
+// @Failure      404 {object} dto.BaseResponse
+// @Failure      401 {object} dto.BaseResponse
+// @Security     ApiKeyAuth
+// @Router       /addresses/{id}/webhooks/{webhookId}/deliveries/{deliveryId} [get]
+func (h *DeliveryHandler) Get(c echo.Context) error {
+	addressID, webhookID, appErr := parseWebhookPath(c)
+	if appErr != nil {
+		return response.SendAppError(c, appErr)
+	}
+	deliveryID, appErr := parseID(c, "deliveryId", "delivery")
+	if appErr != nil {
+		return response.SendAppError(c, appErr)
+	}
+
+	delivery, appErr := h.deliveryService.Get(c.Request().Context(), middleware.UserID(c), addressID, webhookID, deliveryID)
+	if appErr != nil {
+		h.logger.WithError(appErr).Error("Failed to retrieve delivery")
+		return response.SendAppError(c, appErr)
+	}
+
+	return response.SendSuccess(c, http.StatusOK, "Delivery retrieved successfully", delivery)
+}
+
+// Redeliver godoc
+// @Summary      Send a delivery again
+// @Description  Queue a delivered or failed delivery for another attempt with a fresh retry budget
+// @Tags         deliveries
+// @Produce      json
+// @Param        id path string true "Address ID"
+// @Param        webhookId path string true "Webhook ID"
+// @Param        deliveryId path string true "Delivery ID"
+// @Success      202 {object} dto.DeliveryResponse
+// @Failure      400 {object} dto.BaseResponse
+// @Failure      404 {object} dto.BaseResponse
+// @Failure      401 {object} dto.BaseResponse
+// @Security     ApiKeyAuth
+// @Router       /addresses/{id}/webhooks/{webhookId}/deliveries/{deliveryId}/redeliver [post]
+func (h *DeliveryHandler) Redeliver(c echo.Context) error {
+	addressID, webhookID, appErr := parseWebhookPath(c)
+	if appErr != nil {
+		return response.SendAppError(c, appErr)
+	}
+	deliveryID, appErr := parseID(c, "deliveryId", "delivery")
+	if appErr != nil {
+		return response.SendAppError(c, appErr)
+	}
+
+	delivery, appErr := h.deliveryService.Redeliver(c.Request().Context(), middleware.UserID(c), addressID, webhookID, deliveryID)
+	if appErr != nil {
+		h.logger.WithError(appErr).Error("Failed to redeliver")
+		return response.SendAppError(c, appErr)
+	}
+
+	return response.SendSuccess(c, http.StatusAccepted, "Redelivery queued successfully", delivery)
+}
+
+// RedeliverFailed godoc
+// @Summary      Send failed deliveries again
+// @Description  Queue every failed delivery of the webhook created within the time window
+// @Tags         deliveries
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Address ID"
+// @Param        webhookId path string true "Webhook ID"
+// @Param        payload body dto.RedeliverFailedRequest true "Redelivery Request"
+// @Success      202 {object} dto.RedeliveryResponse
+// @Failure      400 {object} dto.BaseResponse
+// @Failure      404 {object} dto.BaseResponse
+// @Failure      401 {object} dto.BaseResponse
+// @Security     ApiKeyAuth
+// @Router       /addresses/{id}/webhooks/{webhookId}/redeliver [post]
+func (h *DeliveryHandler) RedeliverFailed(c echo.Context) error {
+	addressID, webhookID, appErr := parseWebhookPath(c)
+	if appErr != nil {
+		return response.SendAppError(c, appErr)
+	}
+
+	var request dto.RedeliverFailedRequest
+	if err := c.Bind(&request); err != nil {
+		h.logger.WithError(err).Error("Failed to bind request")
+		return response.SendAppError(c, errors.ValidationError("Invalid JSON format"))
+	}
+
+	if err := h.validator.Validate(request); err != nil {
+		h.logger.WithError(err).Error("Failed to validate request")
+		return response.SendValidationError(c, h.validator, err)
+	}
+
+	result, appErr := h.deliveryService.RedeliverFailed(c.Request().Context(), middleware.UserID(c), addressID, webhookID, &request)
+	if appErr != nil {
+		h.logger.WithError(appErr).Error("Failed to redeliver failed deliveries")
+		return response.SendAppError(c, appErr)
+	}
+
+	return response.SendSuccess(c, http.StatusAccepted, "Redeliveries queued successfully", result)
+}
+
+// parseWebhookPath reads the address and webhook IDs of a webhook sub-resource path
+func parseWebhookPath(c echo.Context) (uuid.UUID, uuid.UUID, *errors.AppError) {
+	addressID, appErr := parseID(c, "id", "address")
+	if appErr != nil {
+		return uuid.Nil, uuid.Nil, appErr
+	}
+	webhookID, appErr := parseID(c, "webhookId", "webhook")
+	if appErr != nil {
+		return uuid.Nil, uuid.Nil, appErr
+	}
+	return addressID, webhookID, nil
+}
+
+// splitList accepts both repeated and comma separated query values
+func splitList(values []string) []string {
+	var list []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+	return list
+}
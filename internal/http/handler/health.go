@@ -4,23 +4,75 @@ import (
 	"net/http"
 	"time"
 
+	"evm-tx-watcher/internal/buildinfo"
+	"evm-tx-watcher/internal/health"
+
 	"github.com/labstack/echo/v4"
 )
 
+// Probe statuses
+const (
+	HealthStatusOK       = "ok"
+	HealthStatusReady    = "ready"
+	HealthStatusNotReady = "not_ready"
+)
+
 type HealthResponse struct {
-	Status    string    `json:"status"`
-	Timestamp time.Time `json:"timestamp"`
-	Service   string    `json:"service"`
-	Version   string    `json:"version,omitempty"`
+	Status    string                  `json:"status"`
+	Timestamp time.Time               `json:"timestamp"`
+	Service   string                  `json:"service"`
+	Build     buildinfo.Info          `json:"build"`
+	Checks    map[string]health.Check `json:"checks,omitempty"`
+	Migration *health.Migration       `json:"migration,omitempty"`
+}
+
+type HealthHandler struct {
+	checker *health.Checker
+	service string
+}
+
+// NewHealthHandler serves the probes of service, e.g. evm-tx-watcher-worker
+func NewHealthHandler(checker *health.Checker, service string) *HealthHandler {
+	return &HealthHandler{checker: checker, service: service}
 }
 
-func HealthHandler(c echo.Context) error {
-	response := HealthResponse{
-		Status:    "healthy",
+// Live godoc
+// @Summary      Liveness probe
+// @Description  Reports that the process is up and which build it runs. Dependencies are not checked, so an outage of Postgres or Redis does not get the process restarted.
+// @Tags         health
+// @Produce      json
+// @Success      200 {object} HealthResponse
+// @Router       /health/live [get]
+func (h *HealthHandler) Live(c echo.Context) error {
+	return c.JSON(http.StatusOK, HealthResponse{
+		Status:    HealthStatusOK,
 		Timestamp: time.Now(),
-		Service:   "evm-tx-watcher",
-		Version:   "1.0.0",
-	}
+		Service:   h.service,
+		Build:     buildinfo.Get(),
+	})
+}
 
-	return c.JSON(http.StatusOK, response)
+// Ready godoc
+// @Summary      Readiness probe
+// @Description  Pings Postgres and Redis and reads the applied migration version. Answers 503 when any check fails or the last migration is dirty.
+// @Tags         health
+// @Produce      json
+// @Success      200 {object} HealthResponse
+// @Failure      503 {object} HealthResponse
+// @Router       /health/ready [get]
+func (h *HealthHandler) Ready(c echo.Context) error {
+	readiness := h.checker.Ready(c.Request().Context())
+
+	status, code := HealthStatusReady, http.StatusOK
+	if !readiness.Ready {
+		status, code = HealthStatusNotReady, http.StatusServiceUnavailable
+	}
+	return c.JSON(code, HealthResponse{
+		Status:    status,
+		Timestamp: time.Now(),
+		Service:   h.service,
+		Build:     buildinfo.Get(),
+		Checks:    readiness.Checks,
+		Migration: readiness.Migration,
+	})
 }
@@ -0,0 +1,258 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"evm-tx-watcher/internal/domain"
+	"evm-tx-watcher/internal/dto"
+	"evm-tx-watcher/internal/errors"
+	"evm-tx-watcher/internal/http/middleware"
+	"evm-tx-watcher/internal/http/response"
+	"evm-tx-watcher/internal/stream"
+	"evm-tx-watcher/internal/util"
+	"evm-tx-watcher/internal/validator"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+)
+
+// wsWriteTimeout bounds each write to a WebSocket client
+const wsWriteTimeout = 10 * time.Second
+
+type StreamHandler struct {
+	hub       *stream.Hub
+	logger    *util.Logger
+	validator *validator.Validator
+	upgrader  websocket.Upgrader
+}
+
+func NewStreamHandler(
+	hub *stream.Hub,
+	allowedOrigins []string,
+	logger *util.Logger,
+	validator *validator.Validator,
+) *StreamHandler {
+	return &StreamHandler{
+		hub:       hub,
+		logger:    logger,
+		validator: validator,
+		upgrader:  websocket.Upgrader{CheckOrigin: originChecker(allowedOrigins)},
+	}
+}
+
+// originChecker admits WebSocket handshakes from the allowed browser origins,
+// so a page elsewhere cannot stream with a key it got hold of. Without an
+// allowlist only same-origin pages may connect. Clients that send no Origin
+// header are not browsers and are always admitted.
+func originChecker(allowed []string) func(*http.Request) bool {
+	if len(allowed) == 0 {
+		return nil // the upgrader's same-origin check
+	}
+
+	origins := make(map[string]bool, len(allowed))
+	for _, origin := range allowed {
+		if origin == "*" {
+			return func(*http.Request) bool { return true }
+		}
+		origins[strings.ToLower(strings.TrimSuffix(origin, "/"))] = true
+	}
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		return origin == "" || origins[strings.ToLower(origin)]
+	}
+}
+
+// Events godoc
+// @Summary      Stream events over Server-Sent Events
+// @Description  Pushes the user's transaction, token transfer and reorg events as they are persisted. Each event carries an id; reconnect with Last-Event-ID or last_event_id to receive what was missed, as far as the bounded history reaches.
+// @Tags         stream
+// @Produce      text/event-stream
+// @Param        chain_id query string false "Comma separated chain IDs"
+// @Param        address query string false "Comma separated addresses"
+// @Param        events query string false "Comma separated event types or families: transaction, token_transfer, block, transaction.confirmed, ..."
+// @Param        last_event_id query string false "Resume after this event ID"
+// @Success      200 {string} string "event stream"
+// @Failure      400 {object} dto.BaseResponse
+// @Failure      401 {object} dto.BaseResponse
+// @Security     ApiKeyAuth
+// @Router       /stream [get]
+func (h *StreamHandler) Events(c echo.Context) error {
+	var request dto.StreamRequest
+	if err := c.Bind(&request); err != nil {
+		h.logger.WithError(err).Error("Failed to bind request")
+		return response.SendAppError(c, errors.ValidationError("Invalid query parameters"))
+	}
+	request.ChainIDs = splitList(request.ChainIDs)
+	request.Addresses = splitList(request.Addresses)
+	request.Events = splitList(request.Events)
+
+	if err := h.validator.Validate(request); err != nil {
+		h.logger.WithError(err).Error("Failed to validate request")
+		return response.SendValidationError(c, h.validator, err)
+	}
+
+	filter, lastEventID, appErr := newStreamFilter(c, &request)
+	if appErr != nil {
+		return response.SendAppError(c, appErr)
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no") // keep nginx from buffering the stream
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	err := h.hub.Follow(c.Request().Context(), filter, lastEventID, &sseSink{res: res})
+	if err != nil {
+		h.logger.WithError(err).Warn("[Stream] closed event stream")
+	}
+	return nil
+}
+
+// WebSocket godoc
+// @Summary      Stream events over a WebSocket
+// @Description  Same events and filters as the Server-Sent Events stream, sent as JSON text messages. Browsers may only connect from the origins in STREAM_ALLOWED_ORIGINS, or the API's own origin when none are configured.
+// @Tags         stream
+// @Param        chain_id query string false "Comma separated chain IDs"
+// @Param        address query string false "Comma separated addresses"
+// @Param        events query string false "Comma separated event types or families"
+// @Param        last_event_id query string false "Resume after this event ID"
+// @Success      101 {string} string "switching protocols"
+// @Failure      400 {object} dto.BaseResponse
+// @Failure      401 {object} dto.BaseResponse
+// @Security     ApiKeyAuth
+// @Router       /stream/ws [get]
+func (h *StreamHandler) WebSocket(c echo.Context) error {
+	var request dto.StreamRequest
+	if err := c.Bind(&request); err != nil {
+		h.logger.WithError(err).Error("Failed to bind request")
+		return response.SendAppError(c, errors.ValidationError("Invalid query parameters"))
+	}
+	request.ChainIDs = splitList(request.ChainIDs)
+	request.Addresses = splitList(request.Addresses)
+	request.Events = splitList(request.Events)
+
+	if err := h.validator.Validate(request); err != nil {
+		h.logger.WithError(err).Error("Failed to validate request")
+		return response.SendValidationError(c, h.validator, err)
+	}
+
+	filter, lastEventID, appErr := newStreamFilter(c, &request)
+	if appErr != nil {
+		return response.SendAppError(c, appErr)
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		// The upgrader already answered the request
+		h.logger.WithError(err).Warn("[Stream] failed to upgrade connection")
+		return nil
+	}
+	defer conn.Close()
+
+	// Reading processes control frames and notices the client going away
+	ctx, cancel := context.WithCancel(c.Request().Context())
+	defer cancel()
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	err = h.hub.Follow(ctx, filter, lastEventID, &wsSink{conn: conn})
+	if err != nil {
+		h.logger.WithError(err).Warn("[Stream] closed websocket stream")
+	}
+
+	code, reason := websocket.CloseNormalClosure, ""
+	switch err {
+	case stream.ErrSlowClient:
+		code, reason = websocket.CloseTryAgainLater, err.Error()
+	case stream.ErrHubStopped:
+		code, reason = websocket.CloseGoingAway, err.Error()
+	}
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), time.Now().Add(wsWriteTimeout))
+	return nil
+}
+
+// newStreamFilter builds the filter of a stream request and the event ID to resume after
+func newStreamFilter(c echo.Context, request *dto.StreamRequest) (stream.Filter, string, *errors.AppError) {
+	// EventSource sends the last seen ID as a header when it reconnects
+	lastEventID := c.Request().Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = request.LastEventID
+	}
+	if lastEventID != "" && !stream.IsEventID(lastEventID) {
+		return stream.Filter{}, "", errors.ValidationError("Invalid last event ID")
+	}
+
+	filter := stream.Filter{
+		UserID:    middleware.UserID(c),
+		ChainIDs:  make(map[int64]bool),
+		Addresses: make(map[string]bool),
+		Types:     request.Events,
+	}
+	for _, value := range request.ChainIDs {
+		chainID, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return stream.Filter{}, "", errors.ValidationError("Invalid chain ID")
+		}
+		filter.ChainIDs[chainID] = true
+	}
+	for _, address := range request.Addresses {
+		filter.Addresses[strings.ToLower(address)] = true
+	}
+	return filter, lastEventID, nil
+}
+
+// sseSink writes events in the Server-Sent Events format
+type sseSink struct {
+	res *echo.Response
+}
+
+func (s *sseSink) Send(event *domain.StreamEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(s.res, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data); err != nil {
+		return err
+	}
+	s.res.Flush()
+	return nil
+}
+
+func (s *sseSink) Ping() error {
+	if _, err := fmt.Fprint(s.res, ": ping\n\n"); err != nil {
+		return err
+	}
+	s.res.Flush()
+	return nil
+}
+
+// wsSink writes events as JSON text messages
+type wsSink struct {
+	conn *websocket.Conn
+}
+
+func (s *wsSink) Send(event *domain.StreamEvent) error {
+	if err := s.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout)); err != nil {
+		return err
+	}
+	return s.conn.WriteJSON(event)
+}
+
+func (s *wsSink) Ping() error {
+	return s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout))
+}
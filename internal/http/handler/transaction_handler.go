@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"evm-tx-watcher/internal/dto"
+	"evm-tx-watcher/internal/errors"
+	"evm-tx-watcher/internal/http/middleware"
+	"evm-tx-watcher/internal/http/response"
+	"evm-tx-watcher/internal/service"
+	"evm-tx-watcher/internal/util"
+	"evm-tx-watcher/internal/validator"
+	"net/http"
+	"regexp"
+
+	"github.com/labstack/echo/v4"
+)
+
+var txHashPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{64}$`)
+
+type TransactionHandler struct {
+	transactionService service.TransactionService
+	logger             *util.Logger
+	validator          *validator.Validator
+}
+
+func NewTransactionHandler(
+	transactionService service.TransactionService,
+	logger *util.Logger,
+	validator *validator.Validator,
+) *TransactionHandler {
+	return &TransactionHandler{
+		transactionService: transactionService,
+		logger:             logger,
+		validator:          validator,
+	}
+}
+
+// List godoc
+// @Summary      List recorded transactions
+// @Description  Newest first, paginated with the opaque next_cursor of the previous page
+// @Tags         transactions
+// @Produce      json
+// @Param        chain_id query int false "Chain ID"
+// @Param        address query string false "Sender or recipient of the transaction or of one of its transfers"
+// @Param        from_block query int false "Lowest block number"
+// @Param        to_block query int false "Highest block number"
+// @Param        from_time query string false "Earliest block time, RFC 3339"
+// @Param        to_time query string false "Latest block time, RFC 3339"
+// @Param        status query int false "1 for success, 0 for failed"
+// @Param        token_address query string false "Token contract of a transfer in the transaction"
+// @Param        include_removed query bool false "Include transactions reorged out of the chain"
+// @Param        cursor query string false "next_cursor of the previous page"
+// @Param        limit query int false "Page size, 1-200, default 50"
+// @Success      200 {object} dto.TransactionListResponse
+// @Failure      400 {object} dto.BaseResponse
+// @Failure      401 {object} dto.BaseResponse
+// @Security     ApiKeyAuth
+// @Router       /transactions [get]
+func (h *TransactionHandler) List(c echo.Context) error {
+	var request dto.ListTransactionsRequest
+	if err := c.Bind(&request); err != nil {
+		h.logger.WithError(err).Error("Failed to bind request")
+		return response.SendAppError(c, errors.ValidationError("Invalid query parameters"))
+	}
+
+	if err := h.validator.Validate(request); err != nil {
+		h.logger.WithError(err).Error("Failed to validate request")
+		return response.SendValidationError(c, h.validator, err)
+	}
+
+	transactions, appErr := h.transactionService.List(c.Request().Context(), middleware.UserID(c), &request)
+	if appErr != nil {
+		h.logger.WithError(appErr).Error("Failed to list transactions")
+		return response.SendAppError(c, appErr)
+	}
+
+	return response.SendSuccess(c, http.StatusOK, "Transactions retrieved successfully", transactions)
+}
+
+// GetByHash godoc
+// @Summary      Get a transaction
+// @Description  Retrieve a transaction with its token and internal transfers
+// @Tags         transactions
+// @Produce      json
+// @Param        hash path string true "Transaction hash"
+// @Success      200 {object} domain.Transaction
+// @Failure      400 {object} dto.BaseResponse
+// @Failure      404 {object} dto.BaseResponse
+// @Failure      401 {object} dto.BaseResponse
+// @Security     ApiKeyAuth
+// @Router       /transactions/{hash} [get]
+func (h *TransactionHandler) GetByHash(c echo.Context) error {
+	hash := c.Param("hash")
+	if !txHashPattern.MatchString(hash) {
+		return response.SendAppError(c, errors.ValidationError("Invalid transaction hash"))
+	}
+
+	transaction, appErr := h.transactionService.GetByHash(c.Request().Context(), middleware.UserID(c), hash)
+	if appErr != nil {
+		h.logger.WithError(appErr).Error("Failed to retrieve transaction")
+		return response.SendAppError(c, appErr)
+	}
+
+	return response.SendSuccess(c, http.StatusOK, "Transaction retrieved successfully", transaction)
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"evm-tx-watcher/internal/dto"
+	"evm-tx-watcher/internal/errors"
+	"evm-tx-watcher/internal/http/middleware"
+	"evm-tx-watcher/internal/http/response"
+	"evm-tx-watcher/internal/service"
+	"evm-tx-watcher/internal/util"
+	"evm-tx-watcher/internal/validator"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type WebhookHandler struct {
+	webhookService service.WebhookService
+	logger         *util.Logger
+	validator      *validator.Validator
+}
+
+func NewWebhookHandler(
+	webhookService service.WebhookService,
+	logger *util.Logger,
+	validator *validator.Validator,
+) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+		logger:         logger,
+		validator:      validator,
+	}
+}
+
+// List godoc
+// @Summary      List the webhooks of an address
+// @Tags         webhooks
+// @Produce      json
+// @Param        id path string true "Address ID"
+// @Success      200 {array} dto.WebhookResponse
+// @Failure      400 {object} dto.BaseResponse
+// @Failure      404 {object} dto.BaseResponse
+// @Failure      401 {object} dto.BaseResponse
+// @Security     ApiKeyAuth
+// @Router       /addresses/{id}/webhooks [get]
+func (h *WebhookHandler) List(c echo.Context) error {
+	addressID, appErr := parseID(c, "id", "address")
+	if appErr != nil {
+		return response.SendAppError(c, appErr)
+	}
+
+	webhooks, appErr := h.webhookService.List(c.Request().Context(), middleware.UserID(c), addressID)
+	if appErr != nil {
+		h.logger.WithError(appErr).Error("Failed to retrieve webhooks")
+		return response.SendAppError(c, appErr)
+	}
+
+	return response.SendSuccess(c, http.StatusOK, "Webhooks retrieved successfully", webhooks)
+}
+
+// Create godoc
+// @Summary      Add a webhook to an address
+// @Description  Notify another URL about the address; events filters by incoming, outgoing, token and native activity
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Address ID"
+// @Param        payload body dto.CreateWebhookRequest true "Webhook Request"
+// @Success      201 {object} dto.WebhookResponse
+// @Failure      400 {object} dto.BaseResponse
+// @Failure      404 {object} dto.BaseResponse
+// @Failure      401 {object} dto.BaseResponse
+// @Security     ApiKeyAuth
+// @Router       /addresses/{id}/webhooks [post]
+func (h *WebhookHandler) Create(c echo.Context) error {
+	addressID, appErr := parseID(c, "id", "address")
+	if appErr != nil {
+		return response.SendAppError(c, appErr)
+	}
+
+	var request dto.CreateWebhookRequest
+	if err := c.Bind(&request); err != nil {
+		h.logger.WithError(err).Error("Failed to bind request")
+		return response.SendAppError(c, errors.ValidationError("Invalid JSON format"))
+	}
+
+	if err := h.validator.Validate(request); err != nil {
+		h.logger.WithError(err).Error("Failed to validate request")
+		return response.SendValidationError(c, h.validator, err)
+	}
+
+	webhook, appErr := h.webhookService.Create(c.Request().Context(), middleware.UserID(c), addressID, &request)
+	if appErr != nil {
+		h.logger.WithError(appErr).Error("Failed to create webhook")
+		return response.SendAppError(c, appErr)
+	}
+
+	return response.SendSuccess(c, http.StatusCreated, "Webhook created successfully", webhook)
+}
+
+// Update godoc
+// @Summary      Update a webhook
+// @Description  Change the URL, secret or event filter of a webhook, or enable and disable it with is_active
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "Address ID"
+// @Param        webhookId path string true "Webhook ID"
+// @Param        payload body dto.UpdateWebhookRequest true "Update Request"
+// @Success      200 {object} dto.WebhookResponse
+// @Failure      400 {object} dto.BaseResponse
+// @Failure      404 {object} dto.BaseResponse
+// @Failure      401 {object} dto.BaseResponse
+// @Security     ApiKeyAuth
+// @Router       /addresses/{id}/webhooks/{webhookId} [patch]
+func (h *WebhookHandler) Update(c echo.Context) error {
+	addressID, appErr := parseID(c, "id", "address")
+	if appErr != nil {
+		return response.SendAppError(c, appErr)
+	}
+	webhookID, appErr := parseID(c, "webhookId", "webhook")
+	if appErr != nil {
+		return response.SendAppError(c, appErr)
+	}
+
+	var request dto.UpdateWebhookRequest
+	if err := c.Bind(&request); err != nil {
+		h.logger.WithError(err).Error("Failed to bind request")
+		return response.SendAppError(c, errors.ValidationError("Invalid JSON format"))
+	}
+
+	if err := h.validator.Validate(request); err != nil {
+		h.logger.WithError(err).Error("Failed to validate request")
+		return response.SendValidationError(c, h.validator, err)
+	}
+
+	webhook, appErr := h.webhookService.Update(c.Request().Context(), middleware.UserID(c), addressID, webhookID, &request)
+	if appErr != nil {
+		h.logger.WithError(appErr).Error("Failed to update webhook")
+		return response.SendAppError(c, appErr)
+	}
+
+	return response.SendSuccess(c, http.StatusOK, "Webhook updated successfully", webhook)
+}
+
+// Delete godoc
+// @Summary      Remove a webhook
+// @Tags         webhooks
+// @Produce      json
+// @Param        id path string true "Address ID"
+// @Param        webhookId path string true "Webhook ID"
+// @Success      200 {object} dto.BaseResponse
+// @Failure      400 {object} dto.BaseResponse
+// @Failure      404 {object} dto.BaseResponse
+// @Failure      401 {object} dto.BaseResponse
+// @Security     ApiKeyAuth
+// @Router       /addresses/{id}/webhooks/{webhookId} [delete]
+func (h *WebhookHandler) Delete(c echo.Context) error {
+	addressID, appErr := parseID(c, "id", "address")
+	if appErr != nil {
+		return response.SendAppError(c, appErr)
+	}
+	webhookID, appErr := parseID(c, "webhookId", "webhook")
+	if appErr != nil {
+		return response.SendAppError(c, appErr)
+	}
+
+	if appErr := h.webhookService.Delete(c.Request().Context(), middleware.UserID(c), addressID, webhookID); appErr != nil {
+		h.logger.WithError(appErr).Error("Failed to delete webhook")
+		return response.SendAppError(c, appErr)
+	}
+
+	return response.SendSuccess(c, http.StatusOK, "Webhook deleted successfully", nil)
+}
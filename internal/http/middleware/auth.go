@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"strings"
+
+	"evm-tx-watcher/internal/http/response"
+	"evm-tx-watcher/internal/service"
+	"evm-tx-watcher/internal/util"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// userIDKey is the echo context key holding the authenticated user
+const userIDKey = "user_id"
+
+// APIKeyAuth rejects requests without a valid API key and stores the key's user
+// in the context. The key is read from the X-API-Key header or a bearer token,
+// or from the api_key query parameter for browser EventSource and WebSocket
+// clients, which cannot set headers.
+func APIKeyAuth(apiKeyService service.APIKeyService, logger *util.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := c.Request().Header.Get("X-API-Key")
+			if key == "" {
+				if auth := c.Request().Header.Get(echo.HeaderAuthorization); strings.HasPrefix(auth, "Bearer ") {
+					key = strings.TrimPrefix(auth, "Bearer ")
+				}
+			}
+			if key == "" {
+				key = c.QueryParam("api_key")
+			}
+
+			userID, appErr := apiKeyService.Authenticate(c.Request().Context(), key)
+			if appErr != nil {
+				logger.WithError(appErr).Warn("Rejected request")
+				return response.SendAppError(c, appErr)
+			}
+
+			c.Set(userIDKey, userID)
+			return next(c)
+		}
+	}
+}
+
+// UserID returns the user authenticated by APIKeyAuth
+func UserID(c echo.Context) uuid.UUID {
+	userID, _ := c.Get(userIDKey).(uuid.UUID)
+	return userID
+}
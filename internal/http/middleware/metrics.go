@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"evm-tx-watcher/internal/metrics"
+
+	"github.com/labstack/echo/v4"
+)
+
+// MetricsMiddleware counts requests and their duration by route template, so
+// /addresses/:id is one series rather than one per address
+func MetricsMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			started := time.Now()
+			err := next(c)
+			if err != nil {
+				// Write the error response now so its status is counted; the
+				// error handler skips responses that are already committed
+				c.Error(err)
+			}
+
+			route := c.Path()
+			if route == "" {
+				route = "unmatched"
+			}
+			method := c.Request().Method
+			status := strconv.Itoa(c.Response().Status)
+
+			metrics.HTTPRequests.WithLabelValues(method, route, status).Inc()
+			metrics.HTTPDuration.WithLabelValues(method, route).Observe(time.Since(started).Seconds())
+			return err
+		}
+	}
+}
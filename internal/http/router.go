@@ -1,11 +1,17 @@
 package http
 
 import (
+	"context"
+
+	"evm-tx-watcher/internal/cache"
 	"evm-tx-watcher/internal/config"
+	"evm-tx-watcher/internal/health"
 	"evm-tx-watcher/internal/http/handler"
 	"evm-tx-watcher/internal/http/middleware"
+	"evm-tx-watcher/internal/metrics"
 	"evm-tx-watcher/internal/repository"
 	"evm-tx-watcher/internal/service"
+	"evm-tx-watcher/internal/stream"
 	"evm-tx-watcher/internal/util"
 	"evm-tx-watcher/internal/validator"
 
@@ -14,37 +20,99 @@ import (
 	echomiddleware "github.com/labstack/echo/v4/middleware"
 )
 
-func NewRouter(cfg *config.Config, db *sqlx.DB, logger *util.Logger, validator *validator.Validator) *echo.Echo {
+// NewRouter builds the API server. Background work such as the stream hub runs
+// until ctx is cancelled.
+func NewRouter(ctx context.Context, cfg *config.Config, db *sqlx.DB, redis *cache.RedisClient, logger *util.Logger, validator *validator.Validator) *echo.Echo {
 	e := echo.New()
 
 	e.HideBanner = false
 
 	// Add middlewares
+	e.Use(middleware.MetricsMiddleware())
 	e.Use(middleware.LoggingMiddleware(logger))
 	e.Use(echomiddleware.Recover())
 	e.Use(echomiddleware.CORS())
 
 	// Setup routes
-	setupRoutes(e, db, logger, validator)
+	setupRoutes(ctx, e, cfg, db, redis, logger, validator)
 
 	return e
 }
 
-func setupRoutes(e *echo.Echo, db *sqlx.DB, logger *util.Logger, validator *validator.Validator) {
+func setupRoutes(ctx context.Context, e *echo.Echo, cfg *config.Config, db *sqlx.DB, redis *cache.RedisClient, logger *util.Logger, validator *validator.Validator) {
+
+	// Health probes; /health is kept for existing liveness checks
+	healthHandler := handler.NewHealthHandler(health.NewChecker(db, redis), "evm-tx-watcher-api")
+	e.GET("/health", healthHandler.Live)
+	e.GET("/health/live", healthHandler.Live)
+	e.GET("/health/ready", healthHandler.Ready)
 
-	// Health check endpoint
-	e.GET("/health", handler.HealthHandler)
+	// Prometheus scrape endpoint
+	e.GET("/metrics", echo.WrapHandler(metrics.Handler()))
 
 	unitOfWork := repository.NewUnitOfWork(db)
 	addrRepo := repository.NewAddressRepository(db)
 	webhookRepo := repository.NewWebhookRepository(db)
 
-	addrService := service.NewAddressService(unitOfWork, addrRepo, webhookRepo)
+	apiKeyService := service.NewAPIKeyService(unitOfWork, repository.NewAPIKeyRepository(db))
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService, logger, validator)
+
+	addrService := service.NewAddressService(unitOfWork, addrRepo, webhookRepo, redis)
 	addrHandler := handler.NewAddressHandler(addrService, logger, validator)
 
-	v1 := e.Group("/api/v1")
+	webhookService := service.NewWebhookService(unitOfWork, addrRepo, webhookRepo, redis)
+	webhookHandler := handler.NewWebhookHandler(webhookService, logger, validator)
+
+	deliveryService := service.NewDeliveryService(
+		unitOfWork,
+		addrRepo,
+		webhookRepo,
+		repository.NewWebhookDeliveryRepository(db),
+		repository.NewWebhookDeliveryAttemptRepository(db),
+		redis,
+		logger,
+	)
+	deliveryHandler := handler.NewDeliveryHandler(deliveryService, logger, validator)
+
+	txService := service.NewTransactionService(
+		repository.NewTransactionRepository(db),
+		repository.NewTokenTransferRepository(db),
+		repository.NewInternalTransferRepository(db),
+	)
+	txHandler := handler.NewTransactionHandler(txService, logger, validator)
+
+	// One hub per replica relays the workers' events to its stream clients
+	hub := stream.NewHub(redis, logger)
+	go hub.Run(ctx)
+	streamHandler := handler.NewStreamHandler(hub, cfg.Stream.AllowedOrigins, logger, validator)
+
+	// Every API route acts for the user of the request's API key
+	v1 := e.Group("/api/v1", middleware.APIKeyAuth(apiKeyService, logger))
 	{
+		v1.GET("/api-keys", apiKeyHandler.List)
+		v1.POST("/api-keys", apiKeyHandler.Create)
+		v1.DELETE("/api-keys/:id", apiKeyHandler.Revoke)
+
 		v1.GET("/addresses", addrHandler.GetAll)
 		v1.POST("/addresses", addrHandler.Register)
+		v1.GET("/addresses/:id", addrHandler.GetByID)
+		v1.PATCH("/addresses/:id", addrHandler.Update)
+		v1.DELETE("/addresses/:id", addrHandler.Delete)
+
+		v1.GET("/addresses/:id/webhooks", webhookHandler.List)
+		v1.POST("/addresses/:id/webhooks", webhookHandler.Create)
+		v1.PATCH("/addresses/:id/webhooks/:webhookId", webhookHandler.Update)
+		v1.DELETE("/addresses/:id/webhooks/:webhookId", webhookHandler.Delete)
+		v1.POST("/addresses/:id/webhooks/:webhookId/redeliver", deliveryHandler.RedeliverFailed)
+
+		v1.GET("/addresses/:id/webhooks/:webhookId/deliveries", deliveryHandler.List)
+		v1.GET("/addresses/:id/webhooks/:webhookId/deliveries/:deliveryId", deliveryHandler.Get)
+		v1.POST("/addresses/:id/webhooks/:webhookId/deliveries/:deliveryId/redeliver", deliveryHandler.Redeliver)
+
+		v1.GET("/transactions", txHandler.List)
+		v1.GET("/transactions/:hash", txHandler.GetByHash)
+
+		v1.GET("/stream", streamHandler.Events)
+		v1.GET("/stream/ws", streamHandler.WebSocket)
 	}
 }
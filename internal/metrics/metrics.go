@@ -0,0 +1,150 @@
+// Package metrics defines the Prometheus metrics of every process. Collectors
+// register with the default registry; each process serves the ones it updates.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "evm_tx_watcher"
+
+// RPC client
+var (
+	RPCRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rpc_requests_total",
+		Help:      "RPC calls per endpoint attempt, by outcome (success, not_found, error).",
+	}, []string{"network", "method", "outcome"})
+
+	RPCDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "rpc_request_duration_seconds",
+		Help:      "Duration of RPC calls per endpoint attempt.",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+	}, []string{"network", "method"})
+
+	ReceiptsFetched = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "receipts_fetched_total",
+		Help:      "Transaction receipts fetched.",
+	}, []string{"network"})
+)
+
+// Block watcher
+var (
+	ChainHead = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "chain_head_block",
+		Help:      "Newest block header seen.",
+	}, []string{"network"})
+
+	ConfirmedBlock = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "confirmed_block",
+		Help:      "Newest block released as confirmed.",
+	}, []string{"network"})
+
+	HeadLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "head_lag_blocks",
+		Help:      "Blocks between the chain head and the newest confirmed block.",
+	}, []string{"network"})
+
+	BlocksConfirmed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "blocks_confirmed_total",
+		Help:      "Blocks released to the processor as confirmed.",
+	}, []string{"network"})
+
+	BlocksReorged = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "blocks_reorged_total",
+		Help:      "Released blocks retracted because they left the canonical chain.",
+	}, []string{"network"})
+
+	BlocksDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "blocks_dropped_total",
+		Help:      "Blocks not released in order: skipped beyond the catch-up window, or pending headers that left the reorg window and are refetched.",
+	}, []string{"network", "reason"})
+)
+
+// Processor
+var (
+	BlocksProcessed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "blocks_processed_total",
+		Help:      "Block events handled by the processor, by event and outcome (success, error).",
+	}, []string{"network", "event", "outcome"})
+
+	BlockProcessingDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "block_processing_duration_seconds",
+		Help:      "Time to match and persist one block event.",
+		Buckets:   prometheus.ExponentialBuckets(0.005, 2, 12),
+	}, []string{"network", "event"})
+
+	MatchesFound = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "matches_found_total",
+		Help:      "Transactions matching a watched address.",
+	}, []string{"network", "event"})
+)
+
+// Webhook delivery
+var (
+	WebhookDeliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_deliveries_total",
+		Help:      "Webhook delivery attempts by outcome (delivered, failed, max_retries_exceeded).",
+	}, []string{"outcome"})
+
+	WebhookDeliveryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "webhook_delivery_duration_seconds",
+		Help:      "Duration of webhook delivery attempts.",
+		Buckets:   prometheus.ExponentialBuckets(0.025, 2, 10),
+	}, []string{"outcome"})
+)
+
+// HTTP API
+var (
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "API requests by route template and status.",
+	}, []string{"method", "route", "status"})
+
+	HTTPDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "API request duration by route template.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+)
+
+// RegisterBlockQueue exposes the depth of a network's block queue
+func RegisterBlockQueue(network string, depth func() int) {
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   namespace,
+		Name:        "block_queue_depth",
+		Help:        "Block events waiting for the processor.",
+		ConstLabels: prometheus.Labels{"network": network},
+	}, func() float64 { return float64(depth()) })
+}
+
+// RegisterWebhookQueue exposes the number of queued or in-flight webhook deliveries
+func RegisterWebhookQueue(length func() (int64, error)) {
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "webhook_queue_length",
+		Help:      "Webhook deliveries queued or in flight; -1 when the queue cannot be read.",
+	}, func() float64 {
+		n, err := length()
+		if err != nil {
+			return -1
+		}
+		return float64(n)
+	})
+}
@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"evm-tx-watcher/internal/util"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Handler serves the registered metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Server is a dedicated /metrics listener for processes without an API
+type Server struct {
+	server *http.Server
+	logger *util.Logger
+}
+
+func NewServer(port string, logger *util.Logger) *Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+
+	return &Server{
+		server: &http.Server{Addr: ":" + port, Handler: mux, ReadHeaderTimeout: 5 * time.Second},
+		logger: logger,
+	}
+}
+
+// Start serves until ctx is cancelled
+func (s *Server) Start(ctx context.Context) {
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = s.server.Shutdown(shutdownCtx)
+	}()
+
+	s.logger.Infof("[Metrics] Listening on %s", s.server.Addr)
+	if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		s.logger.WithError(err).Error("[Metrics] Server stopped")
+	}
+}
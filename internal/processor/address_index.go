@@ -0,0 +1,194 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"evm-tx-watcher/internal/blockchain/client"
+	"evm-tx-watcher/internal/cache"
+	"evm-tx-watcher/internal/domain"
+	"evm-tx-watcher/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// indexRefreshInterval is how often the cached watched address list is checked for changes
+const indexRefreshInterval = 2 * time.Second
+
+type watchKey struct {
+	chainID int64
+	address string
+}
+
+// addressIndex is an in-memory lookup of watched addresses keyed by (chain_id, address).
+// The API invalidates the Redis copy whenever addresses change; the index reloads
+// from Postgres on a cache miss and rebuilds whenever the cached list differs.
+type addressIndex struct {
+	addressRepo repository.AddressRepository
+	redis       *cache.RedisClient
+
+	mu          sync.RWMutex
+	entries     map[watchKey][]domain.WatchedAddress
+	fingerprint string
+	checkedAt   time.Time
+}
+
+func newAddressIndex(addressRepo repository.AddressRepository, redis *cache.RedisClient) *addressIndex {
+	return &addressIndex{
+		addressRepo: addressRepo,
+		redis:       redis,
+		entries:     make(map[watchKey][]domain.WatchedAddress),
+	}
+}
+
+// refresh reloads the index if the watched address list changed since the last check
+func (i *addressIndex) refresh(ctx context.Context, force bool) error {
+	i.mu.RLock()
+	fresh := !force && time.Since(i.checkedAt) < indexRefreshInterval
+	i.mu.RUnlock()
+	if fresh {
+		return nil
+	}
+
+	addresses, err := i.redis.GetWatchedAddresses(ctx)
+	if err != nil {
+		return err
+	}
+
+	if addresses == nil {
+		// Cache miss: the list was invalidated or expired, load it from Postgres.
+		// The version is read first, so an invalidation racing the load keeps
+		// the possibly stale list out of the cache for everyone else.
+		version, err := i.redis.WatchedAddressesVersion(ctx)
+		if err != nil {
+			return err
+		}
+		stored, err := i.addressRepo.GetWatchedAddresses(ctx)
+		if err != nil {
+			return err
+		}
+		addresses = make([]domain.WatchedAddress, 0, len(stored))
+		for _, addr := range stored {
+			addresses = append(addresses, *addr)
+		}
+		// A skipped write leaves the cache empty, the next refresh loads again
+		if err := i.redis.CacheWatchedAddresses(ctx, addresses, version); err != nil {
+			return err
+		}
+	}
+
+	fingerprint := fingerprintOf(addresses)
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.checkedAt = time.Now()
+	if fingerprint == i.fingerprint {
+		return nil
+	}
+
+	entries := make(map[watchKey][]domain.WatchedAddress, len(addresses))
+	for _, addr := range addresses {
+		if !addr.IsActive {
+			continue
+		}
+		key := watchKey{chainID: addr.ChainID, address: strings.ToLower(addr.Address)}
+		entries[key] = append(entries[key], addr)
+	}
+	i.entries = entries
+	i.fingerprint = fingerprint
+
+	return nil
+}
+
+// lookup returns the watch entries of an address on a chain
+func (i *addressIndex) lookup(chainID int64, address string) []domain.WatchedAddress {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.entries[watchKey{chainID: chainID, address: strings.ToLower(address)}]
+}
+
+// activity is one way a transaction touches an address
+type activity struct {
+	address   string
+	direction domain.WebhookEventFilter
+	kind      domain.WebhookEventFilter
+}
+
+// match returns the watch entries, one per webhook, whose address appears as the
+// sender or recipient of the transaction or of any of its token or internal
+// transfers, in a way the webhook's event filter accepts
+func (i *addressIndex) match(chainID int64, details *client.TransactionDetails) []domain.WatchedAddress {
+	activities := []activity{{details.Transaction.FromAddress, domain.WebhookFilterOutgoing, domain.WebhookFilterNative}}
+	if details.Transaction.ToAddress != nil {
+		activities = append(activities, activity{*details.Transaction.ToAddress, domain.WebhookFilterIncoming, domain.WebhookFilterNative})
+	}
+	for _, transfer := range details.TokenTransfers {
+		activities = append(activities,
+			activity{transfer.FromAddress, domain.WebhookFilterOutgoing, domain.WebhookFilterToken},
+			activity{transfer.ToAddress, domain.WebhookFilterIncoming, domain.WebhookFilterToken})
+	}
+	for _, transfer := range details.InternalTransfers {
+		activities = append(activities,
+			activity{transfer.FromAddress, domain.WebhookFilterOutgoing, domain.WebhookFilterNative},
+			activity{transfer.ToAddress, domain.WebhookFilterIncoming, domain.WebhookFilterNative})
+	}
+
+	var matched []domain.WatchedAddress
+	seen := make(map[uuid.UUID]bool)
+	for _, a := range activities {
+		for _, entry := range i.lookup(chainID, a.address) {
+			if seen[entry.WebhookID] || !domain.AcceptsActivity(entry.Events, a.direction, a.kind) {
+				continue
+			}
+			seen[entry.WebhookID] = true
+			matched = append(matched, entry)
+		}
+	}
+
+	return matched
+}
+
+// owners returns the users watching any of the addresses on a chain,
+// regardless of their webhooks' event filters
+func (i *addressIndex) owners(chainID int64, addresses ...string) []uuid.UUID {
+	var users []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	for _, address := range addresses {
+		for _, entry := range i.lookup(chainID, address) {
+			if entry.UserID == nil || seen[*entry.UserID] {
+				continue
+			}
+			seen[*entry.UserID] = true
+			users = append(users, *entry.UserID)
+		}
+	}
+	return users
+}
+
+// fingerprintOf identifies a watched address list so unchanged lists are not re-indexed
+func fingerprintOf(addresses []domain.WatchedAddress) string {
+	var b strings.Builder
+	for _, addr := range addresses {
+		fmt.Fprintf(&b, "%d:%s:%s:%t:%s;", addr.ChainID, strings.ToLower(addr.Address), addr.WebhookID, addr.IsActive,
+			strings.Join(addr.Events, ","))
+	}
+	return b.String()
+}
+
+// addresses returns the distinct active addresses watched on a chain
+func (i *addressIndex) addresses(chainID int64) []string {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	var addresses []string
+	for key := range i.entries {
+		if key.chainID == chainID {
+			addresses = append(addresses, key.address)
+		}
+	}
+	return addresses
+}
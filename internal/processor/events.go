@@ -0,0 +1,102 @@
+package processor
+
+import (
+	"context"
+	"time"
+
+	"evm-tx-watcher/internal/domain"
+	"evm-tx-watcher/internal/stream"
+)
+
+// outbox collects what a database transaction produced; it is handed out
+// only once the transaction commits
+type outbox struct {
+	deliveries []*domain.WebhookDelivery
+	events     []*stream.Envelope
+}
+
+// flush queues the deliveries and publishes the stream events of a committed transaction
+func (p *Processor) flush(ctx context.Context, out *outbox) {
+	p.enqueueDeliveries(ctx, out.deliveries)
+	p.publisher.Publish(ctx, out.events)
+}
+
+// addTransactionEvents adds the stream events of a persisted transaction: the
+// transaction itself for every user watching one of its parties, and each token
+// transfer for the users watching its sender or recipient
+func (p *Processor) addTransactionEvents(out *outbox, eventType domain.WebhookEventType, transaction *domain.Transaction) {
+	now := time.Now()
+	chainID := transaction.ChainID
+
+	if users := p.index.owners(chainID, transactionParties(transaction)...); len(users) > 0 {
+		out.events = append(out.events, &stream.Envelope{
+			Users: users,
+			Event: &domain.StreamEvent{
+				Type:        string(eventType),
+				ChainID:     chainID,
+				Transaction: transaction,
+				Timestamp:   now,
+			},
+		})
+	}
+
+	transferType := tokenTransferEventType(eventType)
+	if transferType == "" {
+		return
+	}
+	for i := range transaction.TokenTransfers {
+		transfer := &transaction.TokenTransfers[i]
+		users := p.index.owners(chainID, transfer.FromAddress, transfer.ToAddress)
+		if len(users) == 0 {
+			continue
+		}
+		out.events = append(out.events, &stream.Envelope{
+			Users: users,
+			Event: &domain.StreamEvent{
+				Type:          transferType,
+				ChainID:       chainID,
+				TokenTransfer: transfer,
+				Timestamp:     now,
+			},
+		})
+	}
+}
+
+// addReorgEvent adds a chain-wide notice about an orphaned block
+func (p *Processor) addReorgEvent(out *outbox, chainID, blockNumber int64, blockHash string) {
+	out.events = append(out.events, &stream.Envelope{
+		Event: &domain.StreamEvent{
+			Type:      domain.StreamEventBlockReorged,
+			ChainID:   chainID,
+			Reorg:     &domain.ReorgNotice{BlockNumber: blockNumber, BlockHash: blockHash},
+			Timestamp: time.Now(),
+		},
+	})
+}
+
+// tokenTransferEventType maps a transaction event to the event of its token
+// transfers; reverted transfers are covered by the transaction event
+func tokenTransferEventType(eventType domain.WebhookEventType) string {
+	switch eventType {
+	case domain.WebhookEventTransactionIncluded:
+		return domain.StreamEventTokenTransferIncluded
+	case domain.WebhookEventTransactionConfirmed:
+		return domain.StreamEventTokenTransferConfirmed
+	}
+	return ""
+}
+
+// transactionParties returns every address a transaction moved value between
+func transactionParties(transaction *domain.Transaction) []string {
+	parties := []string{transaction.FromAddress}
+	if transaction.ToAddress != nil {
+		parties = append(parties, *transaction.ToAddress)
+	}
+	for _, transfer := range transaction.TokenTransfers {
+		parties = append(parties, transfer.FromAddress, transfer.ToAddress)
+	}
+	for _, transfer := range transaction.InternalTransfers {
+		parties = append(parties, transfer.FromAddress, transfer.ToAddress)
+	}
+	return parties
+}
@@ -0,0 +1,113 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"evm-tx-watcher/internal/blockchain/client"
+	"evm-tx-watcher/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// WatchedAddresses returns the active addresses watched on a chain
+func (p *Processor) WatchedAddresses(chainID int64) []string {
+	return p.index.addresses(chainID)
+}
+
+// StoreHistorical persists confirmed transactions found by a backfill that
+// touch one of the addresses in only, or any watched address when only is nil.
+// Transactions already stored are left untouched, so a backfill can be re-run
+// over blocks the worker has seen. Webhooks receive transaction.confirmed
+// deliveries only when notify is set. It returns how many transactions were stored.
+func (p *Processor) StoreHistorical(ctx context.Context, chainID int64, details []*client.TransactionDetails, only map[string]bool, notify bool) (int, error) {
+	var matches []*match
+	for _, d := range details {
+		var watched []domain.WatchedAddress
+		for _, entry := range p.index.match(chainID, d) {
+			if only == nil || only[strings.ToLower(entry.Address)] {
+				watched = append(watched, entry)
+			}
+		}
+		if len(watched) > 0 {
+			matches = append(matches, &match{details: d, watched: watched})
+		}
+	}
+	if len(matches) == 0 {
+		return 0, nil
+	}
+
+	for _, m := range matches {
+		p.tokens.Enrich(ctx, chainID, m.details.TokenTransfers)
+	}
+
+	stored := 0
+	var deliveries []*domain.WebhookDelivery
+	err := p.unitOfWork.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		stored, deliveries = 0, nil
+		for _, m := range matches {
+			created, ok, err := p.persistHistorical(ctx, tx, m, notify)
+			if err != nil {
+				return err
+			}
+			if ok {
+				stored++
+			}
+			deliveries = append(deliveries, created...)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to store historical transactions: %w", err)
+	}
+
+	p.enqueueDeliveries(ctx, deliveries)
+	return stored, nil
+}
+
+// persistHistorical stores a backfilled transaction unless a live copy exists
+func (p *Processor) persistHistorical(ctx context.Context, tx *sqlx.Tx, m *match, notify bool) ([]*domain.WebhookDelivery, bool, error) {
+	transaction := m.details.Transaction
+
+	existing, err := p.txRepo.FindLiveByHash(ctx, tx, transaction.ChainID, transaction.Hash)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		return nil, false, nil
+	}
+
+	if _, err := p.txRepo.Create(ctx, tx, transaction); err != nil {
+		return nil, false, err
+	}
+	for i := range m.details.TokenTransfers {
+		if _, err := p.transferRepo.Create(ctx, tx, &m.details.TokenTransfers[i]); err != nil {
+			return nil, false, err
+		}
+	}
+	for i := range m.details.InternalTransfers {
+		if err := p.internalRepo.Create(ctx, tx, &m.details.InternalTransfers[i]); err != nil {
+			return nil, false, err
+		}
+	}
+	transaction.TokenTransfers = m.details.TokenTransfers
+	transaction.InternalTransfers = m.details.InternalTransfers
+
+	if !notify {
+		return nil, true, nil
+	}
+
+	var deliveries []*domain.WebhookDelivery
+	for _, watched := range m.watched {
+		delivery, err := newDelivery(ctx, watched.WebhookID, domain.WebhookEventTransactionConfirmed, transaction)
+		if err != nil {
+			return nil, false, err
+		}
+		if _, err := p.deliveryRepo.Create(ctx, tx, delivery); err != nil {
+			return nil, false, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, true, nil
+}
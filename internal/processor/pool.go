@@ -0,0 +1,95 @@
+package processor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"evm-tx-watcher/internal/blockchain/watcher"
+)
+
+const (
+	// retryBaseDelay is the first pause before a failed block is handled again
+	retryBaseDelay = time.Second
+	// retryMaxDelay caps the pause between attempts at the same block
+	retryMaxDelay = 30 * time.Second
+)
+
+// Pool consumes the per-network block queues with a bounded number of
+// concurrent workers. Each queue is drained by a single lane, so blocks of one
+// chain are handled strictly in order while different chains run in parallel.
+type Pool struct {
+	processor *Processor
+	slots     chan struct{}
+}
+
+func NewPool(processor *Processor, workers int) *Pool {
+	return &Pool{
+		processor: processor,
+		slots:     make(chan struct{}, workers),
+	}
+}
+
+// Run processes the queues until ctx is cancelled
+func (p *Pool) Run(ctx context.Context, queues []*watcher.BlockQueue) {
+	p.processor.log.Infof("[Processor] Starting %d workers for %d networks", cap(p.slots), len(queues))
+
+	var wg sync.WaitGroup
+	for _, queue := range queues {
+		wg.Add(1)
+		go func(queue *watcher.BlockQueue) {
+			defer wg.Done()
+			p.lane(ctx, queue)
+		}(queue)
+	}
+	wg.Wait()
+
+	p.processor.log.Info("[Processor] Stopped")
+}
+
+// lane handles one network's events in order. A failed event is retried with
+// backoff rather than skipped, holding back the events queued behind it.
+func (p *Pool) lane(ctx context.Context, queue *watcher.BlockQueue) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-queue.Events():
+			delay := retryBaseDelay
+			for !p.handle(ctx, event) {
+				p.processor.log.Warnf("[Processor] %s: retrying block %d in %s",
+					queue.Network(), event.Header.Number.Uint64(), delay)
+				select {
+				case <-time.After(delay):
+				case <-ctx.Done():
+					return
+				}
+				delay = min(delay*2, retryMaxDelay)
+			}
+			if ctx.Err() != nil {
+				return // shutting down, the event may not have been handled
+			}
+			queue.MarkProcessed(event)
+		}
+	}
+}
+
+// handle processes event while holding a worker slot and reports success
+func (p *Pool) handle(ctx context.Context, event *watcher.BlockEvent) bool {
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return true
+	}
+	defer func() { <-p.slots }()
+
+	if err := p.processor.HandleBlock(ctx, event); err != nil {
+		if ctx.Err() != nil {
+			return true
+		}
+		p.processor.log.WithError(err).Errorf("[Processor] %s: failed to process block %d",
+			event.NetworkConfig.Name, event.Header.Number.Uint64())
+		return false
+	}
+	return true
+}
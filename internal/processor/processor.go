@@ -2,59 +2,420 @@ package processor
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"time"
 
+	"evm-tx-watcher/internal/blockchain/client"
+	"evm-tx-watcher/internal/blockchain/watcher"
+	"evm-tx-watcher/internal/cache"
+	"evm-tx-watcher/internal/domain"
+	"evm-tx-watcher/internal/metrics"
+	"evm-tx-watcher/internal/repository"
+	"evm-tx-watcher/internal/stream"
+	"evm-tx-watcher/internal/token"
+	"evm-tx-watcher/internal/tracing"
 	"evm-tx-watcher/internal/util"
 
-	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+const (
+	// defaultMaxRetries matches the webhook_deliveries.max_retries column default
+	defaultMaxRetries = 3
+	// deliveryLease matches the dispatcher's retry lease: a new delivery that is
+	// still pending after it, because queueing failed or the entry was lost, is
+	// picked up by the retry scheduler
+	deliveryLease = 5 * time.Minute
+)
+
+// match is a transaction that touches at least one watched address
+type match struct {
+	details *client.TransactionDetails
+	watched []domain.WatchedAddress
+}
+
 type Processor struct {
-	log *util.Logger
+	log          *util.Logger
+	unitOfWork   repository.UnitOfWork
+	txRepo       repository.TransactionRepository
+	transferRepo repository.TokenTransferRepository
+	internalRepo repository.InternalTransferRepository
+	deliveryRepo repository.WebhookDeliveryRepository
+	cursorRepo   repository.BlockCursorRepository
+	redis        *cache.RedisClient
+	tokens       *token.MetadataService
+	publisher    *stream.Publisher
+	index        *addressIndex
+}
+
+func New(
+	log *util.Logger,
+	unitOfWork repository.UnitOfWork,
+	addressRepo repository.AddressRepository,
+	txRepo repository.TransactionRepository,
+	transferRepo repository.TokenTransferRepository,
+	internalRepo repository.InternalTransferRepository,
+	deliveryRepo repository.WebhookDeliveryRepository,
+	cursorRepo repository.BlockCursorRepository,
+	redis *cache.RedisClient,
+	tokens *token.MetadataService,
+	publisher *stream.Publisher,
+) *Processor {
+	return &Processor{
+		log:          log,
+		unitOfWork:   unitOfWork,
+		txRepo:       txRepo,
+		transferRepo: transferRepo,
+		internalRepo: internalRepo,
+		deliveryRepo: deliveryRepo,
+		cursorRepo:   cursorRepo,
+		redis:        redis,
+		tokens:       tokens,
+		publisher:    publisher,
+		index:        newAddressIndex(addressRepo, redis),
+	}
+}
+
+// LoadWatchedAddresses builds the watched address index before the first block arrives
+func (p *Processor) LoadWatchedAddresses(ctx context.Context) error {
+	return p.index.refresh(ctx, true)
+}
+
+// HandleBlock records a block event and its duration and outcome in the
+// metrics. Its span continues the trace the watcher started for the block.
+func (p *Processor) HandleBlock(ctx context.Context, event *watcher.BlockEvent) error {
+	ctx, span := tracing.Start(trace.ContextWithSpanContext(ctx, event.Trace), "processor.HandleBlock",
+		attribute.String("network", event.NetworkConfig.Name),
+		attribute.String("block.event", string(event.Type)),
+		attribute.Int64("block.number", event.Header.Number.Int64()),
+	)
+	started := time.Now()
+	err := p.handleBlock(ctx, event)
+	tracing.End(span, err)
+
+	network, eventType := event.NetworkConfig.Name, string(event.Type)
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+	metrics.BlocksProcessed.WithLabelValues(network, eventType, outcome).Inc()
+	metrics.BlockProcessingDuration.WithLabelValues(network, eventType).Observe(time.Since(started).Seconds())
+	return err
 }
 
-func New(log *util.Logger) *Processor {
-	return &Processor{log: log}
+func (p *Processor) handleBlock(ctx context.Context, event *watcher.BlockEvent) error {
+	switch event.Type {
+	case watcher.BlockEventReorged:
+		return p.handleReorg(ctx, event)
+	case watcher.BlockEventIncluded:
+		return p.handleTransactions(ctx, event, domain.WebhookEventTransactionIncluded)
+	default:
+		return p.handleTransactions(ctx, event, domain.WebhookEventTransactionConfirmed)
+	}
 }
 
-func (p *Processor) HandleBlock(ctx context.Context, blk *types.Block) error {
-	p.log.Infof("[Processor] block=%d hash=%s txs=%d",
-		blk.NumberU64(), blk.Hash().Hex(), len(blk.Transactions()))
+// handleTransactions stores the block's matching transactions and notifies
+// their webhooks with eventType. Only confirmed blocks advance the cursor.
+func (p *Processor) handleTransactions(ctx context.Context, event *watcher.BlockEvent, eventType domain.WebhookEventType) error {
+
+	if err := p.index.refresh(ctx, false); err != nil {
+		p.log.WithError(err).Warn("[Processor] failed to refresh watched addresses, using previous set")
+	}
+
+	blk := event.Block
+	chainID := event.NetworkConfig.ChainID
+
+	_, span := tracing.Start(ctx, "processor.match", attribute.Int("block.transactions", len(event.TransactionDetails)))
+	var matches []*match
+	for _, details := range event.TransactionDetails {
+		if watched := p.index.match(chainID, details); len(watched) > 0 {
+			matches = append(matches, &match{details: details, watched: watched})
+		}
+	}
+	span.SetAttributes(attribute.Int("matches", len(matches)))
+	span.End()
+
+	// Resolve token metadata before opening the database transaction, only
+	// matched transfers are worth the eth_calls
+	for _, m := range matches {
+		p.tokens.Enrich(ctx, chainID, m.details.TokenTransfers)
+	}
+
+	cursor := &domain.BlockCursor{
+		ChainID:     chainID,
+		Network:     event.NetworkConfig.Name,
+		BlockNumber: blk.Number().Int64(),
+		BlockHash:   blk.Hash().Hex(),
+	}
 
-	// Process transactions if available
-	if len(blk.Transactions()) > 0 {
-		for i, tx := range blk.Transactions() {
-			// Limit logging to first 3 transactions to avoid spam
-			if i >= 3 {
-				p.log.Infof("[Processor] ... and %d more transactions", len(blk.Transactions())-3)
-				break
+	// Matches and the cursor are committed together, so a block is either fully
+	// recorded or replayed after a restart
+	var out *outbox
+	err := p.unitOfWork.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		out = &outbox{}
+		for _, m := range matches {
+			if err := p.persistMatch(ctx, tx, m, eventType, out); err != nil {
+				return err
 			}
+		}
+		if eventType != domain.WebhookEventTransactionConfirmed {
+			return nil
+		}
+		return p.cursorRepo.Upsert(ctx, tx, cursor)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to process block %d: %w", blk.NumberU64(), err)
+	}
+
+	if eventType == domain.WebhookEventTransactionConfirmed {
+		p.mirrorCursor(ctx, cursor)
+	}
+	p.flush(ctx, out)
+	metrics.MatchesFound.WithLabelValues(event.NetworkConfig.Name, string(event.Type)).Add(float64(len(matches)))
+
+	p.log.Infof("[Processor] %s block=%d hash=%s txs=%d matched=%d",
+		event.Type, blk.NumberU64(), blk.Hash().Hex(), len(blk.Transactions()), len(matches))
+
+	return nil
+}
+
+// persistMatch stores a matched transaction with its token transfers, creates
+// a pending delivery for every webhook watching one of its addresses and adds
+// its stream events to out. A transaction already stored from the same block,
+// because it was announced at inclusion, is reused; one stored from a
+// different block is reverted first.
+func (p *Processor) persistMatch(ctx context.Context, tx *sqlx.Tx, m *match, eventType domain.WebhookEventType, out *outbox) error {
+	transaction := m.details.Transaction
+
+	existing, err := p.txRepo.FindLiveByHash(ctx, tx, transaction.ChainID, transaction.Hash)
+	if err != nil {
+		return err
+	}
 
-			p.log.Infof("[Processor] tx[%d]: hash=%s from=%s to=%s value=%s gas=%d type=%d",
-				i,
-				tx.Hash().Hex(),
-				p.getSender(tx),
-				p.getRecipient(tx),
-				tx.Value().String(),
-				tx.Gas(),
-				tx.Type(),
-			)
+	if existing != nil && existing.BlockHash != transaction.BlockHash {
+		// Stored from a block that was replaced while nobody was watching
+		if err := p.revertTransaction(ctx, tx, existing, out); err != nil {
+			return err
+		}
+		existing = nil
+	}
+
+	if existing != nil {
+		if eventType == domain.WebhookEventTransactionIncluded {
+			return nil // already announced, e.g. replayed after a restart
+		}
+		transaction.ID = existing.ID
+		stored, err := p.transferRepo.FindByTransactionID(ctx, existing.ID)
+		if err != nil {
+			return err
+		}
+		m.details.TokenTransfers = m.details.TokenTransfers[:0]
+		for _, transfer := range stored {
+			m.details.TokenTransfers = append(m.details.TokenTransfers, *transfer)
+		}
+		internal, err := p.internalRepo.FindByTransactionID(ctx, existing.ID)
+		if err != nil {
+			return err
+		}
+		m.details.InternalTransfers = m.details.InternalTransfers[:0]
+		for _, transfer := range internal {
+			m.details.InternalTransfers = append(m.details.InternalTransfers, *transfer)
 		}
 	} else {
-		p.log.Infof("[Processor] no transaction details available for this block")
+		if _, err := p.txRepo.Create(ctx, tx, transaction); err != nil {
+			return err
+		}
+		for i := range m.details.TokenTransfers {
+			if _, err := p.transferRepo.Create(ctx, tx, &m.details.TokenTransfers[i]); err != nil {
+				return err
+			}
+		}
+		for i := range m.details.InternalTransfers {
+			if err := p.internalRepo.Create(ctx, tx, &m.details.InternalTransfers[i]); err != nil {
+				return err
+			}
+		}
 	}
+	transaction.TokenTransfers = m.details.TokenTransfers
+	transaction.InternalTransfers = m.details.InternalTransfers
+
+	for _, watched := range m.watched {
+		delivery, err := newDelivery(ctx, watched.WebhookID, eventType, transaction)
+		if err != nil {
+			return err
+		}
+		if _, err := p.deliveryRepo.Create(ctx, tx, delivery); err != nil {
+			return err
+		}
+		out.deliveries = append(out.deliveries, delivery)
+	}
+	p.addTransactionEvents(out, eventType, transaction)
+
+	p.log.Infof("[Processor] matched tx=%s chain=%d event=%s webhooks=%d transfers=%d internal=%d",
+		transaction.Hash, transaction.ChainID, eventType, len(m.watched), len(m.details.TokenTransfers), len(m.details.InternalTransfers))
+
+	return nil
+}
+
+// handleReorg marks the stored rows of an orphaned block as removed and tells
+// every webhook that was notified about them that the notification is reverted
+func (p *Processor) handleReorg(ctx context.Context, event *watcher.BlockEvent) error {
+	chainID := event.NetworkConfig.ChainID
+	blockHash := event.Header.Hash().Hex()
+	cursor := &domain.BlockCursor{
+		ChainID:     chainID,
+		Network:     event.NetworkConfig.Name,
+		BlockNumber: event.Header.Number.Int64() - 1,
+		BlockHash:   event.Header.ParentHash.Hex(),
+	}
+
+	var out *outbox
+	err := p.unitOfWork.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		out = &outbox{}
+		removed, err := p.txRepo.MarkRemovedByBlockHash(ctx, tx, chainID, blockHash)
+		if err != nil {
+			return err
+		}
+
+		for _, transaction := range removed {
+			if err := p.notifyReverted(ctx, tx, transaction, out); err != nil {
+				return err
+			}
+		}
+		p.addReorgEvent(out, chainID, event.Header.Number.Int64(), blockHash)
+
+		// Point the cursor at the surviving parent so a restart replays from
+		// there, unless the block was only announced as included
+		current, err := p.cursorRepo.FindByChainID(ctx, chainID)
+		if err != nil {
+			return err
+		}
+		if current == nil || current.BlockNumber < event.Header.Number.Int64() {
+			cursor = nil
+			return nil
+		}
+		return p.cursorRepo.Upsert(ctx, tx, cursor)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to handle reorg of block %s: %w", blockHash, err)
+	}
+
+	if cursor != nil {
+		p.mirrorCursor(ctx, cursor)
+	}
+	p.flush(ctx, out)
+
+	p.log.Warnf("[Processor] reorged block=%d hash=%s chain=%d reverted_notifications=%d",
+		event.Header.Number.Uint64(), blockHash, chainID, len(out.deliveries))
 
 	return nil
 }
 
-func (p *Processor) getSender(tx *types.Transaction) string {
-	// For now, return placeholder since we'd need chain ID and signature recovery
-	// In a full implementation, you'd use types.Sender() with appropriate signer
-	return "0x..." // placeholder
+// revertTransaction marks a single stored transaction as removed and notifies
+// its webhooks
+func (p *Processor) revertTransaction(ctx context.Context, tx *sqlx.Tx, transaction *domain.Transaction, out *outbox) error {
+	if err := p.txRepo.MarkRemoved(ctx, tx, transaction.ID); err != nil {
+		return err
+	}
+	transaction.Removed = true
+	return p.notifyReverted(ctx, tx, transaction, out)
 }
 
-func (p *Processor) getRecipient(tx *types.Transaction) string {
-	if tx.To() != nil {
-		return tx.To().Hex()
+// notifyReverted marks the token and internal transfers of a removed transaction
+// as removed and creates a reverted delivery for every webhook that was notified about it
+func (p *Processor) notifyReverted(ctx context.Context, tx *sqlx.Tx, transaction *domain.Transaction, out *outbox) error {
+	if err := p.transferRepo.MarkRemovedByTransactionID(ctx, tx, transaction.ID); err != nil {
+		return err
+	}
+	if err := p.internalRepo.MarkRemovedByTransactionID(ctx, tx, transaction.ID); err != nil {
+		return err
 	}
-	return "contract_creation"
+
+	webhookIDs, err := p.deliveryRepo.FindWebhookIDsByTransactionID(ctx, tx, transaction.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, webhookID := range webhookIDs {
+		delivery, err := newDelivery(ctx, webhookID, domain.WebhookEventTransactionReverted, transaction)
+		if err != nil {
+			return err
+		}
+		if _, err := p.deliveryRepo.Create(ctx, tx, delivery); err != nil {
+			return err
+		}
+		out.deliveries = append(out.deliveries, delivery)
+	}
+
+	// Stream clients that saw the transaction through one of its transfers need the revert too
+	event := *transaction
+	transfers, err := p.transferRepo.FindByTransactionID(ctx, transaction.ID)
+	if err != nil {
+		return err
+	}
+	for _, transfer := range transfers {
+		event.TokenTransfers = append(event.TokenTransfers, *transfer)
+	}
+	internal, err := p.internalRepo.FindByTransactionID(ctx, transaction.ID)
+	if err != nil {
+		return err
+	}
+	for _, transfer := range internal {
+		event.InternalTransfers = append(event.InternalTransfers, *transfer)
+	}
+	p.addTransactionEvents(out, domain.WebhookEventTransactionReverted, &event)
+	return nil
+}
+
+// mirrorCursor copies the block cursor to Redis; Postgres stays authoritative,
+// so failures are only logged
+func (p *Processor) mirrorCursor(ctx context.Context, cursor *domain.BlockCursor) {
+	if err := p.redis.SetBlockCursor(ctx, cursor); err != nil {
+		p.log.WithError(err).Warnf("[Processor] failed to mirror block cursor for %s", cursor.Network)
+	}
+}
+
+// enqueueDeliveries hands committed deliveries to the dispatcher. Deliveries
+// that fail to queue are retried once their lease expires, see newDelivery.
+func (p *Processor) enqueueDeliveries(ctx context.Context, deliveries []*domain.WebhookDelivery) {
+	for _, delivery := range deliveries {
+		if err := p.redis.QueueWebhookDelivery(ctx, delivery.ID, *delivery.NextRetryAt); err != nil {
+			p.log.WithError(err).Errorf("[Processor] failed to queue webhook delivery %s", delivery.ID)
+		}
+	}
+}
+
+// newDelivery builds a pending webhook delivery for a transaction event. The
+// delivery carries the trace of ctx so its sends join the block's trace, and a
+// lease after which the retry scheduler takes over if it was never delivered.
+func newDelivery(ctx context.Context, webhookID uuid.UUID, eventType domain.WebhookEventType, transaction *domain.Transaction) (*domain.WebhookDelivery, error) {
+	payload, err := json.Marshal(domain.WebhookPayload{
+		Event:       eventType,
+		ChainID:     transaction.ChainID,
+		Transaction: transaction,
+		Timestamp:   time.Now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	now := time.Now()
+	// Postgres keeps microseconds; the queued lease has to match the stored one
+	lease := now.Add(deliveryLease).Truncate(time.Microsecond)
+	return &domain.WebhookDelivery{
+		ID:            uuid.New(),
+		WebhookID:     webhookID,
+		TransactionID: transaction.ID,
+		Payload:       string(payload),
+		Status:        string(domain.WebhookDeliveryStatusPending),
+		MaxRetries:    defaultMaxRetries,
+		NextRetryAt:   &lease,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		TraceParent:   tracing.TraceParent(ctx),
+	}, nil
 }
@@ -14,11 +14,11 @@ import (
 
 type AddressRepository interface {
 	Create(ctx context.Context, tx *sqlx.Tx, address *domain.Address) (domain.Address, error)
-	// Update(ctx context.Context, tx *sqlx.Tx, address *domain.Address) error
-	// Delete(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) error
-	FindByID(ctx context.Context, id uuid.UUID) (*domain.Address, error)
-	FindByAddress(ctx context.Context, address string) (*domain.Address, error)
-	FindAll(ctx context.Context) ([]*domain.Address, error)
+	Update(ctx context.Context, tx *sqlx.Tx, address *domain.Address) error
+	Delete(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) error
+	FindByID(ctx context.Context, userID, id uuid.UUID) (*domain.Address, error)
+	FindByAddress(ctx context.Context, userID uuid.UUID, address string, chainID int) (*domain.Address, error)
+	FindAll(ctx context.Context, userID uuid.UUID) ([]*domain.Address, error)
 	GetWatchedAddresses(ctx context.Context) ([]*domain.WatchedAddress, error)
 }
 
@@ -53,30 +53,69 @@ func (r *addressRepository) Create(ctx context.Context, tx *sqlx.Tx, address *do
 	return *address, nil
 }
 
-func (r *addressRepository) FindAll(ctx context.Context) ([]*domain.Address, error) {
+func (r *addressRepository) Update(ctx context.Context, tx *sqlx.Tx, address *domain.Address) error {
+	query := `
+		UPDATE addresses SET
+			label = $2,
+			description = $3,
+			is_active = $4,
+			updated_at = $5
+		WHERE id = $1`
+
+	_, err := tx.ExecContext(ctx, query,
+		address.ID,
+		address.Label,
+		address.Description,
+		address.IsActive,
+		address.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update address: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes an address; its webhooks are removed by the foreign key cascade
+func (r *addressRepository) Delete(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM addresses WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete address: %w", err)
+	}
+
+	return nil
+}
+
+// FindAll returns the addresses of a user
+func (r *addressRepository) FindAll(ctx context.Context, userID uuid.UUID) ([]*domain.Address, error) {
 	var addresses []*domain.Address
-	query := `SELECT id, address, chain_id, is_contract, is_active, created_at, updated_at, label, description, user_id FROM addresses`
-	err := r.db.SelectContext(ctx, &addresses, query)
+	query := `SELECT id, address, chain_id, is_contract, is_active, created_at, updated_at, label, description, user_id FROM addresses WHERE user_id = $1 ORDER BY created_at`
+	err := r.db.SelectContext(ctx, &addresses, query, userID)
 	if err != nil {
 		return nil, err
 	}
 	return addresses, nil
 }
 
-func (r *addressRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Address, error) {
+// FindByID returns the address if it belongs to the user
+func (r *addressRepository) FindByID(ctx context.Context, userID, id uuid.UUID) (*domain.Address, error) {
 	var address domain.Address
-	query := `SELECT id, address, chain_id, is_contract, is_active, created_at, updated_at, label, description, user_id FROM addresses WHERE id = $1`
-	err := r.db.GetContext(ctx, &address, query, id)
+	query := `SELECT id, address, chain_id, is_contract, is_active, created_at, updated_at, label, description, user_id FROM addresses WHERE id = $1 AND user_id = $2`
+	err := r.db.GetContext(ctx, &address, query, id, userID)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
 		return nil, err
 	}
 	return &address, nil
 }
 
-func (r *addressRepository) FindByAddress(ctx context.Context, addr string) (*domain.Address, error) {
+// FindByAddress returns the user's registration of an address on a chain
+func (r *addressRepository) FindByAddress(ctx context.Context, userID uuid.UUID, addr string, chainID int) (*domain.Address, error) {
 	var address domain.Address
-	query := `SELECT id, address, chain_id, is_contract, is_active, created_at, updated_at, label, description, user_id FROM addresses WHERE address = $1`
-	err := r.db.GetContext(ctx, &address, query, addr)
+	query := `SELECT id, address, chain_id, is_contract, is_active, created_at, updated_at, label, description, user_id FROM addresses WHERE user_id = $1 AND LOWER(address) = LOWER($2) AND chain_id = $3`
+	err := r.db.GetContext(ctx, &address, query, userID, addr, chainID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -94,10 +133,13 @@ func (r *addressRepository) GetWatchedAddresses(ctx context.Context) ([]*domain.
 			a.chain_id,
 			a.is_active,
 			w.id as webhook_id,
-			w.url as webhook_url
+			w.url as webhook_url,
+			w.events,
+			a.user_id
 		FROM addresses a
 		JOIN webhooks w ON a.id = w.address_id
-		WHERE a.is_active = true`
+		WHERE a.is_active = true AND w.is_active = true
+		ORDER BY a.chain_id, a.address, w.id`
 	
 	err := r.db.SelectContext(ctx, &watchedAddresses, query)
 	if err != nil {
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"evm-tx-watcher/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, tx *sqlx.Tx, key *domain.APIKey) error
+	FindByHash(ctx context.Context, keyHash string) (*domain.APIKey, error)
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.APIKey, error)
+	Revoke(ctx context.Context, tx *sqlx.Tx, userID, id uuid.UUID) (bool, error)
+	TouchLastUsed(ctx context.Context, id uuid.UUID) error
+}
+
+type apiKeyRepository struct {
+	db *sqlx.DB
+}
+
+func NewAPIKeyRepository(db *sqlx.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, tx *sqlx.Tx, key *domain.APIKey) error {
+	query := `
+		INSERT INTO api_keys (id, user_id, name, key_prefix, key_hash, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+
+	_, err := tx.ExecContext(ctx, query,
+		key.ID,
+		key.UserID,
+		key.Name,
+		key.KeyPrefix,
+		key.KeyHash,
+		key.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert api key: %w", err)
+	}
+
+	return nil
+}
+
+// FindByHash returns the unrevoked key with the given hash, if any
+func (r *apiKeyRepository) FindByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	var key domain.APIKey
+	query := `
+		SELECT id, user_id, name, key_prefix, key_hash, last_used_at, revoked_at, created_at
+		FROM api_keys
+		WHERE key_hash = $1 AND revoked_at IS NULL`
+
+	err := r.db.GetContext(ctx, &key, query, keyHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find api key by hash: %w", err)
+	}
+
+	return &key, nil
+}
+
+func (r *apiKeyRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.APIKey, error) {
+	var keys []*domain.APIKey
+	query := `
+		SELECT id, user_id, name, key_prefix, key_hash, last_used_at, revoked_at, created_at
+		FROM api_keys
+		WHERE user_id = $1
+		ORDER BY created_at`
+
+	if err := r.db.SelectContext(ctx, &keys, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to find api keys by user ID: %w", err)
+	}
+
+	return keys, nil
+}
+
+// Revoke disables a key of the user and reports whether an active key was revoked
+func (r *apiKeyRepository) Revoke(ctx context.Context, tx *sqlx.Tx, userID, id uuid.UUID) (bool, error) {
+	query := `UPDATE api_keys SET revoked_at = NOW() WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`
+
+	result, err := tx.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+// TouchLastUsed records that a key was used, at most once a minute so busy
+// keys do not turn every request into a write
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE api_keys SET last_used_at = NOW()
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')`
+
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to update api key last use: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"evm-tx-watcher/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type BackfillJobRepository interface {
+	Upsert(ctx context.Context, tx *sqlx.Tx, job *domain.BackfillJob) error
+	FindByID(ctx context.Context, id string) (*domain.BackfillJob, error)
+}
+
+type backfillJobRepository struct {
+	db *sqlx.DB
+}
+
+func NewBackfillJobRepository(db *sqlx.DB) BackfillJobRepository {
+	return &backfillJobRepository{db: db}
+}
+
+func (r *backfillJobRepository) Upsert(ctx context.Context, tx *sqlx.Tx, job *domain.BackfillJob) error {
+	query := `
+		INSERT INTO backfill_jobs (id, chain_id, network, from_block, to_block, next_block,
+			address_count, stored_transactions, completed_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (id) DO UPDATE SET
+			next_block = EXCLUDED.next_block,
+			stored_transactions = EXCLUDED.stored_transactions,
+			completed_at = EXCLUDED.completed_at,
+			updated_at = EXCLUDED.updated_at`
+
+	now := time.Now()
+	if job.CreatedAt.IsZero() {
+		job.CreatedAt = now
+	}
+	job.UpdatedAt = now
+
+	_, err := tx.ExecContext(ctx, query,
+		job.ID,
+		job.ChainID,
+		job.Network,
+		job.FromBlock,
+		job.ToBlock,
+		job.NextBlock,
+		job.AddressCount,
+		job.StoredTransactions,
+		job.CompletedAt,
+		job.CreatedAt,
+		job.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert backfill job: %w", err)
+	}
+
+	return nil
+}
+
+func (r *backfillJobRepository) FindByID(ctx context.Context, id string) (*domain.BackfillJob, error) {
+	var job domain.BackfillJob
+	query := `
+		SELECT id, chain_id, network, from_block, to_block, next_block,
+			address_count, stored_transactions, completed_at, created_at, updated_at
+		FROM backfill_jobs
+		WHERE id = $1`
+
+	err := r.db.GetContext(ctx, &job, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find backfill job: %w", err)
+	}
+
+	return &job, nil
+}
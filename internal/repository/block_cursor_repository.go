@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"evm-tx-watcher/internal/domain"
+	"evm-tx-watcher/internal/tracing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type BlockCursorRepository interface {
+	Upsert(ctx context.Context, tx *sqlx.Tx, cursor *domain.BlockCursor) error
+	FindByChainID(ctx context.Context, chainID int64) (*domain.BlockCursor, error)
+}
+
+type blockCursorRepository struct {
+	db *sqlx.DB
+}
+
+func NewBlockCursorRepository(db *sqlx.DB) BlockCursorRepository {
+	return &blockCursorRepository{db: db}
+}
+
+func (r *blockCursorRepository) Upsert(ctx context.Context, tx *sqlx.Tx, cursor *domain.BlockCursor) error {
+	query := `
+		INSERT INTO block_cursors (chain_id, network, block_number, block_hash, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (chain_id) DO UPDATE SET
+			network = EXCLUDED.network,
+			block_number = EXCLUDED.block_number,
+			block_hash = EXCLUDED.block_hash,
+			updated_at = EXCLUDED.updated_at`
+
+	cursor.UpdatedAt = time.Now()
+
+	ctx, span := tracing.StartQuery(ctx, "UPSERT", "block_cursors")
+	_, err := tx.ExecContext(ctx, query,
+		cursor.ChainID,
+		cursor.Network,
+		cursor.BlockNumber,
+		cursor.BlockHash,
+		cursor.UpdatedAt,
+	)
+	tracing.End(span, err)
+
+	if err != nil {
+		return fmt.Errorf("failed to upsert block cursor: %w", err)
+	}
+
+	return nil
+}
+
+func (r *blockCursorRepository) FindByChainID(ctx context.Context, chainID int64) (*domain.BlockCursor, error) {
+	var cursor domain.BlockCursor
+	query := `
+		SELECT chain_id, network, block_number, block_hash, updated_at
+		FROM block_cursors
+		WHERE chain_id = $1`
+
+	err := r.db.GetContext(ctx, &cursor, query, chainID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find block cursor by chain ID: %w", err)
+	}
+
+	return &cursor, nil
+}
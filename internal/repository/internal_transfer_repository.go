@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"evm-tx-watcher/internal/domain"
+	"evm-tx-watcher/internal/tracing"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type InternalTransferRepository interface {
+	Create(ctx context.Context, tx *sqlx.Tx, transfer *domain.InternalTransfer) error
+	FindByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]*domain.InternalTransfer, error)
+	FindByTransactionIDs(ctx context.Context, transactionIDs []uuid.UUID) ([]*domain.InternalTransfer, error)
+	MarkRemovedByTransactionID(ctx context.Context, tx *sqlx.Tx, transactionID uuid.UUID) error
+}
+
+// internalTransferRow mirrors an internal_transfers row, see transactionRow
+type internalTransferRow struct {
+	domain.InternalTransfer
+	Value sql.NullString `db:"value"`
+}
+
+type internalTransferRepository struct {
+	db *sqlx.DB
+}
+
+func NewInternalTransferRepository(db *sqlx.DB) InternalTransferRepository {
+	return &internalTransferRepository{db: db}
+}
+
+func (r *internalTransferRepository) Create(ctx context.Context, tx *sqlx.Tx, transfer *domain.InternalTransfer) error {
+	query := `
+		INSERT INTO internal_transfers (
+			id, transaction_id, trace_index, call_type, from_address, to_address,
+			value, depth, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9
+		)`
+
+	ctx, span := tracing.StartQuery(ctx, "INSERT", "internal_transfers")
+	_, err := tx.ExecContext(ctx, query,
+		transfer.ID,
+		transfer.TransactionID,
+		transfer.TraceIndex,
+		transfer.CallType,
+		transfer.FromAddress,
+		transfer.ToAddress,
+		transfer.Value.String(),
+		transfer.Depth,
+		transfer.CreatedAt,
+	)
+	tracing.End(span, err)
+	if err != nil {
+		return fmt.Errorf("failed to insert internal transfer: %w", err)
+	}
+
+	return nil
+}
+
+func (r *internalTransferRepository) FindByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]*domain.InternalTransfer, error) {
+	var rows []*internalTransferRow
+	query := `
+		SELECT id, transaction_id, trace_index, call_type, from_address, to_address,
+		       value, depth, removed, created_at
+		FROM internal_transfers
+		WHERE transaction_id = $1
+		ORDER BY trace_index`
+
+	if err := r.db.SelectContext(ctx, &rows, query, transactionID); err != nil {
+		return nil, fmt.Errorf("failed to find internal transfers by transaction ID: %w", err)
+	}
+
+	return toDomainInternalTransfers(rows), nil
+}
+
+// FindByTransactionIDs loads the transfers of several transactions in one query
+func (r *internalTransferRepository) FindByTransactionIDs(ctx context.Context, transactionIDs []uuid.UUID) ([]*domain.InternalTransfer, error) {
+	var rows []*internalTransferRow
+	query := `
+		SELECT id, transaction_id, trace_index, call_type, from_address, to_address,
+		       value, depth, removed, created_at
+		FROM internal_transfers
+		WHERE transaction_id = ANY($1::uuid[])
+		ORDER BY transaction_id, trace_index`
+
+	if err := r.db.SelectContext(ctx, &rows, query, uuidArray(transactionIDs)); err != nil {
+		return nil, fmt.Errorf("failed to find internal transfers by transaction IDs: %w", err)
+	}
+
+	return toDomainInternalTransfers(rows), nil
+}
+
+func toDomainInternalTransfers(rows []*internalTransferRow) []*domain.InternalTransfer {
+	transfers := make([]*domain.InternalTransfer, 0, len(rows))
+	for _, row := range rows {
+		transfer := row.InternalTransfer
+		transfer.Value = parseBigInt(row.Value)
+		transfers = append(transfers, &transfer)
+	}
+	return transfers
+}
+
+func (r *internalTransferRepository) MarkRemovedByTransactionID(ctx context.Context, tx *sqlx.Tx, transactionID uuid.UUID) error {
+	query := `UPDATE internal_transfers SET removed = TRUE WHERE transaction_id = $1`
+
+	ctx, span := tracing.StartQuery(ctx, "UPDATE", "internal_transfers")
+	_, err := tx.ExecContext(ctx, query, transactionID)
+	tracing.End(span, err)
+	if err != nil {
+		return fmt.Errorf("failed to mark internal transfers as removed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"database/sql"
+	"math/big"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// parseBigInt converts a scanned NUMERIC column into a big.Int, returning nil for NULL
+func parseBigInt(value sql.NullString) *big.Int {
+	if !value.Valid {
+		return nil
+	}
+	n, ok := new(big.Int).SetString(value.String, 10)
+	if !ok {
+		return nil
+	}
+	return n
+}
+
+// uuidArray converts ids into a Postgres text array, cast to uuid[] in queries
+func uuidArray(ids []uuid.UUID) pq.StringArray {
+	values := make(pq.StringArray, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, id.String())
+	}
+	return values
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"evm-tx-watcher/internal/domain"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type TokenRepository interface {
+	Upsert(ctx context.Context, tx *sqlx.Tx, token *domain.Token) error
+	FindByAddress(ctx context.Context, chainID int64, address string) (*domain.Token, error)
+}
+
+type tokenRepository struct {
+	db *sqlx.DB
+}
+
+func NewTokenRepository(db *sqlx.DB) TokenRepository {
+	return &tokenRepository{db: db}
+}
+
+func (r *tokenRepository) Upsert(ctx context.Context, tx *sqlx.Tx, token *domain.Token) error {
+	query := `
+		INSERT INTO tokens (chain_id, address, decimals, symbol, name, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (chain_id, address) DO UPDATE SET
+			decimals = EXCLUDED.decimals,
+			symbol = EXCLUDED.symbol,
+			name = EXCLUDED.name,
+			updated_at = EXCLUDED.updated_at`
+
+	now := time.Now()
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = now
+	}
+	token.UpdatedAt = now
+
+	_, err := tx.ExecContext(ctx, query,
+		token.ChainID,
+		token.Address,
+		token.Decimals,
+		token.Symbol,
+		token.Name,
+		token.CreatedAt,
+		token.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *tokenRepository) FindByAddress(ctx context.Context, chainID int64, address string) (*domain.Token, error) {
+	var token domain.Token
+	query := `
+		SELECT chain_id, address, decimals, symbol, name, created_at, updated_at
+		FROM tokens
+		WHERE chain_id = $1 AND address = $2`
+
+	err := r.db.GetContext(ctx, &token, query, chainID, address)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find token: %w", err)
+	}
+
+	return &token, nil
+}
@@ -2,9 +2,11 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 
 	"evm-tx-watcher/internal/domain"
+	"evm-tx-watcher/internal/tracing"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -13,7 +15,31 @@ import (
 type TokenTransferRepository interface {
 	Create(ctx context.Context, tx *sqlx.Tx, transfer *domain.TokenTransfer) (domain.TokenTransfer, error)
 	FindByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]*domain.TokenTransfer, error)
+	FindByTransactionIDs(ctx context.Context, transactionIDs []uuid.UUID) ([]*domain.TokenTransfer, error)
 	FindByTokenAddress(ctx context.Context, tokenAddress string, chainID int64) ([]*domain.TokenTransfer, error)
+	MarkRemovedByTransactionID(ctx context.Context, tx *sqlx.Tx, transactionID uuid.UUID) error
+}
+
+// tokenTransferRow mirrors a token_transfers row, see transactionRow
+type tokenTransferRow struct {
+	domain.TokenTransfer
+	TokenID sql.NullString `db:"token_id"`
+	Value   sql.NullString `db:"value"`
+}
+
+func toDomainTokenTransfers(rows []*tokenTransferRow) []*domain.TokenTransfer {
+	transfers := make([]*domain.TokenTransfer, 0, len(rows))
+	for _, row := range rows {
+		transfer := row.TokenTransfer
+		transfer.TokenID = parseBigInt(row.TokenID)
+		transfer.Value = parseBigInt(row.Value)
+		if transfer.TokenDecimals != nil && transfer.Value != nil {
+			formatted := domain.FormatUnits(transfer.Value, *transfer.TokenDecimals)
+			transfer.FormattedValue = &formatted
+		}
+		transfers = append(transfers, &transfer)
+	}
+	return transfers
 }
 
 type tokenTransferRepository struct {
@@ -27,25 +53,38 @@ func NewTokenTransferRepository(db *sqlx.DB) TokenTransferRepository {
 func (r *tokenTransferRepository) Create(ctx context.Context, tx *sqlx.Tx, transfer *domain.TokenTransfer) (domain.TokenTransfer, error) {
 	query := `
 		INSERT INTO token_transfers (
-			id, transaction_id, log_index, token_address, from_address, to_address,
-			value, token_decimals, token_symbol, token_name, created_at
+			id, transaction_id, log_index, batch_index, standard, token_address,
+			operator_address, from_address, to_address, token_id, value,
+			token_decimals, token_symbol, token_name, created_at
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
 		)`
 
+	var tokenID *string
+	if transfer.TokenID != nil {
+		id := transfer.TokenID.String()
+		tokenID = &id
+	}
+
+	ctx, span := tracing.StartQuery(ctx, "INSERT", "token_transfers")
 	_, err := tx.ExecContext(ctx, query,
 		transfer.ID,
 		transfer.TransactionID,
 		transfer.LogIndex,
+		transfer.BatchIndex,
+		transfer.Standard,
 		transfer.TokenAddress,
+		transfer.OperatorAddress,
 		transfer.FromAddress,
 		transfer.ToAddress,
+		tokenID,
 		transfer.Value.String(), // Store as string to handle big numbers
 		transfer.TokenDecimals,
 		transfer.TokenSymbol,
 		transfer.TokenName,
 		transfer.CreatedAt,
 	)
+	tracing.End(span, err)
 
 	if err != nil {
 		return domain.TokenTransfer{}, fmt.Errorf("failed to insert token transfer: %w", err)
@@ -55,36 +94,70 @@ func (r *tokenTransferRepository) Create(ctx context.Context, tx *sqlx.Tx, trans
 }
 
 func (r *tokenTransferRepository) FindByTransactionID(ctx context.Context, transactionID uuid.UUID) ([]*domain.TokenTransfer, error) {
-	var transfers []*domain.TokenTransfer
+	var transfers []*tokenTransferRow
 	query := `
-		SELECT id, transaction_id, log_index, token_address, from_address, to_address,
-		       value, token_decimals, token_symbol, token_name, created_at
+		SELECT id, transaction_id, log_index, batch_index, standard, token_address,
+		       operator_address, from_address, to_address, token_id, value,
+		       token_decimals, token_symbol, token_name, removed, created_at
 		FROM token_transfers 
 		WHERE transaction_id = $1
-		ORDER BY log_index`
+		ORDER BY log_index, batch_index`
 
 	err := r.db.SelectContext(ctx, &transfers, query, transactionID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find token transfers by transaction ID: %w", err)
 	}
 
-	return transfers, nil
+	return toDomainTokenTransfers(transfers), nil
+}
+
+// FindByTransactionIDs loads the transfers of several transactions in one query
+func (r *tokenTransferRepository) FindByTransactionIDs(ctx context.Context, transactionIDs []uuid.UUID) ([]*domain.TokenTransfer, error) {
+	var transfers []*tokenTransferRow
+	query := `
+		SELECT id, transaction_id, log_index, batch_index, standard, token_address,
+		       operator_address, from_address, to_address, token_id, value,
+		       token_decimals, token_symbol, token_name, removed, created_at
+		FROM token_transfers
+		WHERE transaction_id = ANY($1::uuid[])
+		ORDER BY transaction_id, log_index, batch_index`
+
+	err := r.db.SelectContext(ctx, &transfers, query, uuidArray(transactionIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find token transfers by transaction IDs: %w", err)
+	}
+
+	return toDomainTokenTransfers(transfers), nil
 }
 
 func (r *tokenTransferRepository) FindByTokenAddress(ctx context.Context, tokenAddress string, chainID int64) ([]*domain.TokenTransfer, error) {
-	var transfers []*domain.TokenTransfer
+	var transfers []*tokenTransferRow
 	query := `
-		SELECT tt.id, tt.transaction_id, tt.log_index, tt.token_address, tt.from_address, tt.to_address,
-		       tt.value, tt.token_decimals, tt.token_symbol, tt.token_name, tt.created_at
+		SELECT tt.id, tt.transaction_id, tt.log_index, tt.batch_index, tt.standard, tt.token_address,
+		       tt.operator_address, tt.from_address, tt.to_address, tt.token_id, tt.value,
+		       tt.token_decimals, tt.token_symbol, tt.token_name, tt.removed, tt.created_at
 		FROM token_transfers tt
 		INNER JOIN transactions t ON tt.transaction_id = t.id
 		WHERE tt.token_address = $1 AND t.chain_id = $2
-		ORDER BY t.block_timestamp DESC, tt.log_index`
+		ORDER BY t.block_timestamp DESC, tt.log_index, tt.batch_index`
 
 	err := r.db.SelectContext(ctx, &transfers, query, tokenAddress, chainID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find token transfers by token address: %w", err)
 	}
 
-	return transfers, nil
+	return toDomainTokenTransfers(transfers), nil
+}
+
+func (r *tokenTransferRepository) MarkRemovedByTransactionID(ctx context.Context, tx *sqlx.Tx, transactionID uuid.UUID) error {
+	query := `UPDATE token_transfers SET removed = TRUE WHERE transaction_id = $1`
+
+	ctx, span := tracing.StartQuery(ctx, "UPDATE", "token_transfers")
+	_, err := tx.ExecContext(ctx, query, transactionID)
+	tracing.End(span, err)
+	if err != nil {
+		return fmt.Errorf("failed to mark token transfers as removed: %w", err)
+	}
+
+	return nil
 }
@@ -4,8 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"evm-tx-watcher/internal/domain"
+	"evm-tx-watcher/internal/tracing"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -16,6 +19,58 @@ type TransactionRepository interface {
 	FindByHash(ctx context.Context, hash string) (*domain.Transaction, error)
 	FindByID(ctx context.Context, id uuid.UUID) (*domain.Transaction, error)
 	FindByBlockNumber(ctx context.Context, chainID int64, blockNumber int64) ([]*domain.Transaction, error)
+	FindLiveByHash(ctx context.Context, tx *sqlx.Tx, chainID int64, hash string) (*domain.Transaction, error)
+	MarkRemovedByBlockHash(ctx context.Context, tx *sqlx.Tx, chainID int64, blockHash string) ([]*domain.Transaction, error)
+	MarkRemoved(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) error
+	List(ctx context.Context, filter TransactionFilter) ([]*domain.Transaction, error)
+	InvolvesUser(ctx context.Context, id, userID uuid.UUID) (bool, error)
+}
+
+// TransactionFilter narrows List; nil fields do not filter
+type TransactionFilter struct {
+	UserID         *uuid.UUID // only transactions touching one of the user's addresses
+	ChainID        *int64
+	Address        *string // sender or recipient of the transaction or of one of its transfers
+	FromBlock      *int64
+	ToBlock        *int64
+	FromTime       *time.Time
+	ToTime         *time.Time
+	Status         *int
+	TokenAddress   *string
+	IncludeRemoved bool
+	After          *TransactionCursor // position of the last row of the previous page
+	Limit          int
+}
+
+// TransactionCursor is a keyset position in the newest-first transaction order.
+// The ID breaks ties between chains and between reorged copies.
+type TransactionCursor struct {
+	BlockNumber      int64
+	TransactionIndex int
+	ID               uuid.UUID
+}
+
+// transactionRow mirrors a transactions row. NUMERIC columns are scanned as
+// strings because *big.Int does not implement sql.Scanner.
+type transactionRow struct {
+	domain.Transaction
+	Value    sql.NullString `db:"value"`
+	GasPrice sql.NullString `db:"gas_price"`
+}
+
+func (r *transactionRow) toDomain() *domain.Transaction {
+	transaction := r.Transaction
+	transaction.Value = parseBigInt(r.Value)
+	transaction.GasPrice = parseBigInt(r.GasPrice)
+	return &transaction
+}
+
+func toDomainTransactions(rows []*transactionRow) []*domain.Transaction {
+	transactions := make([]*domain.Transaction, 0, len(rows))
+	for _, row := range rows {
+		transactions = append(transactions, row.toDomain())
+	}
+	return transactions
 }
 
 type transactionRepository struct {
@@ -36,6 +91,7 @@ func (r *transactionRepository) Create(ctx context.Context, tx *sqlx.Tx, transac
 			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
 		)`
 
+	ctx, span := tracing.StartQuery(ctx, "INSERT", "transactions")
 	_, err := tx.ExecContext(ctx, query,
 		transaction.ID,
 		transaction.Hash,
@@ -53,6 +109,7 @@ func (r *transactionRepository) Create(ctx context.Context, tx *sqlx.Tx, transac
 		transaction.BlockTimestamp,
 		transaction.CreatedAt,
 	)
+	tracing.End(span, err)
 
 	if err != nil {
 		return domain.Transaction{}, fmt.Errorf("failed to insert transaction: %w", err)
@@ -61,14 +118,18 @@ func (r *transactionRepository) Create(ctx context.Context, tx *sqlx.Tx, transac
 	return *transaction, nil
 }
 
+// FindByHash returns the transaction with the given hash, preferring the live
+// row over ones that were reorged out
 func (r *transactionRepository) FindByHash(ctx context.Context, hash string) (*domain.Transaction, error) {
-	var transaction domain.Transaction
+	var transaction transactionRow
 	query := `
 		SELECT id, hash, block_number, block_hash, transaction_index, chain_id,
 		       from_address, to_address, value, gas_used, gas_price, tx_type,
-		       status, block_timestamp, created_at
+		       status, block_timestamp, removed, created_at
 		FROM transactions 
-		WHERE hash = $1`
+		WHERE hash = $1
+		ORDER BY removed ASC, block_number DESC
+		LIMIT 1`
 
 	err := r.db.GetContext(ctx, &transaction, query, hash)
 	if err != nil {
@@ -78,15 +139,15 @@ func (r *transactionRepository) FindByHash(ctx context.Context, hash string) (*d
 		return nil, fmt.Errorf("failed to find transaction by hash: %w", err)
 	}
 
-	return &transaction, nil
+	return transaction.toDomain(), nil
 }
 
 func (r *transactionRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Transaction, error) {
-	var transaction domain.Transaction
+	var transaction transactionRow
 	query := `
 		SELECT id, hash, block_number, block_hash, transaction_index, chain_id,
 		       from_address, to_address, value, gas_used, gas_price, tx_type,
-		       status, block_timestamp, created_at
+		       status, block_timestamp, removed, created_at
 		FROM transactions 
 		WHERE id = $1`
 
@@ -98,15 +159,15 @@ func (r *transactionRepository) FindByID(ctx context.Context, id uuid.UUID) (*do
 		return nil, fmt.Errorf("failed to find transaction by ID: %w", err)
 	}
 
-	return &transaction, nil
+	return transaction.toDomain(), nil
 }
 
 func (r *transactionRepository) FindByBlockNumber(ctx context.Context, chainID int64, blockNumber int64) ([]*domain.Transaction, error) {
-	var transactions []*domain.Transaction
+	var transactions []*transactionRow
 	query := `
 		SELECT id, hash, block_number, block_hash, transaction_index, chain_id,
 		       from_address, to_address, value, gas_used, gas_price, tx_type,
-		       status, block_timestamp, created_at
+		       status, block_timestamp, removed, created_at
 		FROM transactions 
 		WHERE chain_id = $1 AND block_number = $2
 		ORDER BY transaction_index`
@@ -116,6 +177,155 @@ func (r *transactionRepository) FindByBlockNumber(ctx context.Context, chainID i
 		return nil, fmt.Errorf("failed to find transactions by block number: %w", err)
 	}
 
-	return transactions, nil
+	return toDomainTransactions(transactions), nil
+}
+
+// FindLiveByHash returns the canonical row of a transaction on a chain, if any
+func (r *transactionRepository) FindLiveByHash(ctx context.Context, tx *sqlx.Tx, chainID int64, hash string) (*domain.Transaction, error) {
+	var transaction transactionRow
+	query := `
+		SELECT id, hash, block_number, block_hash, transaction_index, chain_id,
+		       from_address, to_address, value, gas_used, gas_price, tx_type,
+		       status, block_timestamp, removed, created_at
+		FROM transactions
+		WHERE chain_id = $1 AND hash = $2 AND removed = FALSE`
+
+	err := tx.GetContext(ctx, &transaction, query, chainID, hash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find live transaction by hash: %w", err)
+	}
+
+	return transaction.toDomain(), nil
+}
+
+// MarkRemovedByBlockHash flags every live transaction of an orphaned block as
+// removed and returns the affected rows
+func (r *transactionRepository) MarkRemovedByBlockHash(ctx context.Context, tx *sqlx.Tx, chainID int64, blockHash string) ([]*domain.Transaction, error) {
+	var transactions []*transactionRow
+	query := `
+		UPDATE transactions SET removed = TRUE
+		WHERE chain_id = $1 AND block_hash = $2 AND removed = FALSE
+		RETURNING id, hash, block_number, block_hash, transaction_index, chain_id,
+		          from_address, to_address, value, gas_used, gas_price, tx_type,
+		          status, block_timestamp, removed, created_at`
+
+	ctx, span := tracing.StartQuery(ctx, "UPDATE", "transactions")
+	err := tx.SelectContext(ctx, &transactions, query, chainID, blockHash)
+	tracing.End(span, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark transactions as removed: %w", err)
+	}
+
+	return toDomainTransactions(transactions), nil
+}
+
+func (r *transactionRepository) MarkRemoved(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) error {
+	query := `UPDATE transactions SET removed = TRUE WHERE id = $1`
+
+	ctx, span := tracing.StartQuery(ctx, "UPDATE", "transactions")
+	_, err := tx.ExecContext(ctx, query, id)
+	tracing.End(span, err)
+	if err != nil {
+		return fmt.Errorf("failed to mark transaction as removed: %w", err)
+	}
+
+	return nil
+}
+
+// List returns transactions matching filter, newest first by (block_number,
+// transaction_index), starting after filter.After
+func (r *transactionRepository) List(ctx context.Context, filter TransactionFilter) ([]*domain.Transaction, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if !filter.IncludeRemoved {
+		conditions = append(conditions, "t.removed = FALSE")
+	}
+	if filter.UserID != nil {
+		conditions = append(conditions, userCondition(arg(*filter.UserID)))
+	}
+	if filter.ChainID != nil {
+		conditions = append(conditions, "t.chain_id = "+arg(*filter.ChainID))
+	}
+	if filter.Address != nil {
+		address := arg(*filter.Address)
+		conditions = append(conditions, fmt.Sprintf(`(t.from_address = %[1]s OR t.to_address = %[1]s
+			OR EXISTS (SELECT 1 FROM token_transfers tt WHERE tt.transaction_id = t.id AND (tt.from_address = %[1]s OR tt.to_address = %[1]s))
+			OR EXISTS (SELECT 1 FROM internal_transfers it WHERE it.transaction_id = t.id AND (it.from_address = %[1]s OR it.to_address = %[1]s)))`, address))
+	}
+	if filter.FromBlock != nil {
+		conditions = append(conditions, "t.block_number >= "+arg(*filter.FromBlock))
+	}
+	if filter.ToBlock != nil {
+		conditions = append(conditions, "t.block_number <= "+arg(*filter.ToBlock))
+	}
+	if filter.FromTime != nil {
+		conditions = append(conditions, "t.block_timestamp >= "+arg(*filter.FromTime))
+	}
+	if filter.ToTime != nil {
+		conditions = append(conditions, "t.block_timestamp <= "+arg(*filter.ToTime))
+	}
+	if filter.Status != nil {
+		conditions = append(conditions, "t.status = "+arg(*filter.Status))
+	}
+	if filter.TokenAddress != nil {
+		conditions = append(conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM token_transfers tt WHERE tt.transaction_id = t.id AND tt.token_address = %s)", arg(*filter.TokenAddress)))
+	}
+	if filter.After != nil {
+		conditions = append(conditions, fmt.Sprintf("(t.block_number, t.transaction_index, t.id) < (%s, %s, %s)",
+			arg(filter.After.BlockNumber), arg(filter.After.TransactionIndex), arg(filter.After.ID)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := fmt.Sprintf(`
+		SELECT t.id, t.hash, t.block_number, t.block_hash, t.transaction_index, t.chain_id,
+		       t.from_address, t.to_address, t.value, t.gas_used, t.gas_price, t.tx_type,
+		       t.status, t.block_timestamp, t.removed, t.created_at
+		FROM transactions t
+		%s
+		ORDER BY t.block_number DESC, t.transaction_index DESC, t.id DESC
+		LIMIT %s`, where, arg(filter.Limit))
+
+	var transactions []*transactionRow
+	if err := r.db.SelectContext(ctx, &transactions, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+
+	return toDomainTransactions(transactions), nil
+}
+
+// InvolvesUser reports whether a transaction touches one of the user's addresses
+func (r *transactionRepository) InvolvesUser(ctx context.Context, id, userID uuid.UUID) (bool, error) {
+	var involved bool
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM transactions t WHERE t.id = $1 AND %s)`, userCondition("$2"))
+
+	if err := r.db.GetContext(ctx, &involved, query, id, userID); err != nil {
+		return false, fmt.Errorf("failed to check transaction owner: %w", err)
+	}
+
+	return involved, nil
 }
 
+// userCondition matches transactions t where one of the user's addresses on the
+// same chain is a party to the transaction or to one of its transfers.
+// Registered addresses keep their original case, stored transactions are lower case.
+func userCondition(userID string) string {
+	return fmt.Sprintf(`EXISTS (SELECT 1 FROM addresses a WHERE a.user_id = %s AND a.chain_id = t.chain_id AND (
+			LOWER(a.address) IN (t.from_address, t.to_address)
+			OR EXISTS (SELECT 1 FROM token_transfers tt WHERE tt.transaction_id = t.id AND LOWER(a.address) IN (tt.from_address, tt.to_address))
+			OR EXISTS (SELECT 1 FROM internal_transfers it WHERE it.transaction_id = t.id AND LOWER(a.address) IN (it.from_address, it.to_address))))`, userID)
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"evm-tx-watcher/internal/domain"
+	"evm-tx-watcher/internal/tracing"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type WebhookDeliveryAttemptRepository interface {
+	Create(ctx context.Context, tx *sqlx.Tx, attempt *domain.WebhookDeliveryAttempt) error
+	FindByDeliveryID(ctx context.Context, deliveryID uuid.UUID) ([]*domain.WebhookDeliveryAttempt, error)
+}
+
+type webhookDeliveryAttemptRepository struct {
+	db *sqlx.DB
+}
+
+func NewWebhookDeliveryAttemptRepository(db *sqlx.DB) WebhookDeliveryAttemptRepository {
+	return &webhookDeliveryAttemptRepository{db: db}
+}
+
+func (r *webhookDeliveryAttemptRepository) Create(ctx context.Context, tx *sqlx.Tx, attempt *domain.WebhookDeliveryAttempt) error {
+	query := `
+		INSERT INTO webhook_delivery_attempts (
+			id, delivery_id, http_status_code, response_body, error_message, duration_ms, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7
+		)`
+
+	ctx, span := tracing.StartQuery(ctx, "INSERT", "webhook_delivery_attempts")
+	_, err := tx.ExecContext(ctx, query,
+		attempt.ID,
+		attempt.DeliveryID,
+		attempt.HTTPStatusCode,
+		attempt.ResponseBody,
+		attempt.ErrorMessage,
+		attempt.DurationMs,
+		attempt.CreatedAt,
+	)
+	tracing.End(span, err)
+	if err != nil {
+		return fmt.Errorf("failed to insert webhook delivery attempt: %w", err)
+	}
+
+	return nil
+}
+
+func (r *webhookDeliveryAttemptRepository) FindByDeliveryID(ctx context.Context, deliveryID uuid.UUID) ([]*domain.WebhookDeliveryAttempt, error) {
+	var attempts []*domain.WebhookDeliveryAttempt
+	query := `
+		SELECT id, delivery_id, http_status_code, response_body, error_message, duration_ms, created_at
+		FROM webhook_delivery_attempts
+		WHERE delivery_id = $1
+		ORDER BY created_at`
+
+	if err := r.db.SelectContext(ctx, &attempts, query, deliveryID); err != nil {
+		return nil, fmt.Errorf("failed to find webhook delivery attempts: %w", err)
+	}
+
+	return attempts, nil
+}
@@ -4,20 +4,44 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"evm-tx-watcher/internal/domain"
+	"evm-tx-watcher/internal/tracing"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type WebhookDeliveryRepository interface {
 	Create(ctx context.Context, tx *sqlx.Tx, delivery *domain.WebhookDelivery) (domain.WebhookDelivery, error)
 	Update(ctx context.Context, tx *sqlx.Tx, delivery *domain.WebhookDelivery) error
 	FindByID(ctx context.Context, id uuid.UUID) (*domain.WebhookDelivery, error)
-	FindPendingRetries(ctx context.Context, limit int) ([]*domain.WebhookDelivery, error)
+	FindPendingRetries(ctx context.Context, tx *sqlx.Tx, limit int) ([]*domain.WebhookDelivery, error)
+	Claim(ctx context.Context, id uuid.UUID, seen *time.Time, lease time.Time) (*domain.WebhookDelivery, error)
 	FindByWebhookID(ctx context.Context, webhookID uuid.UUID, limit int) ([]*domain.WebhookDelivery, error)
+	FindWebhookIDsByTransactionID(ctx context.Context, tx *sqlx.Tx, transactionID uuid.UUID) ([]uuid.UUID, error)
+	List(ctx context.Context, filter DeliveryFilter) ([]*domain.WebhookDelivery, error)
+	MarkForRedelivery(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, lease time.Time) (*domain.WebhookDelivery, error)
+	MarkFailedForRedelivery(ctx context.Context, tx *sqlx.Tx, webhookID uuid.UUID, from, to time.Time, lease time.Time) ([]*domain.WebhookDelivery, error)
+}
+
+// DeliveryFilter narrows List; nil and empty fields do not filter
+type DeliveryFilter struct {
+	WebhookID uuid.UUID
+	Statuses  []string
+	FromTime  *time.Time
+	ToTime    *time.Time
+	After     *DeliveryCursor // position of the last row of the previous page
+	Limit     int
+}
+
+// DeliveryCursor is a keyset position in the newest-first delivery order
+type DeliveryCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
 }
 
 type webhookDeliveryRepository struct {
@@ -33,11 +57,12 @@ func (r *webhookDeliveryRepository) Create(ctx context.Context, tx *sqlx.Tx, del
 		INSERT INTO webhook_deliveries (
 			id, webhook_id, transaction_id, payload, status, http_status_code,
 			response_body, error_message, retry_count, max_retries, next_retry_at,
-			delivered_at, created_at, updated_at
+			delivered_at, created_at, updated_at, trace_parent
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15
 		)`
 
+	ctx, span := tracing.StartQuery(ctx, "INSERT", "webhook_deliveries")
 	_, err := tx.ExecContext(ctx, query,
 		delivery.ID,
 		delivery.WebhookID,
@@ -53,7 +78,9 @@ func (r *webhookDeliveryRepository) Create(ctx context.Context, tx *sqlx.Tx, del
 		delivery.DeliveredAt,
 		delivery.CreatedAt,
 		delivery.UpdatedAt,
+		delivery.TraceParent,
 	)
+	tracing.End(span, err)
 
 	if err != nil {
 		return domain.WebhookDelivery{}, fmt.Errorf("failed to insert webhook delivery: %w", err)
@@ -77,6 +104,7 @@ func (r *webhookDeliveryRepository) Update(ctx context.Context, tx *sqlx.Tx, del
 
 	delivery.UpdatedAt = time.Now()
 
+	ctx, span := tracing.StartQuery(ctx, "UPDATE", "webhook_deliveries")
 	_, err := tx.ExecContext(ctx, query,
 		delivery.ID,
 		delivery.Status,
@@ -88,6 +116,7 @@ func (r *webhookDeliveryRepository) Update(ctx context.Context, tx *sqlx.Tx, del
 		delivery.DeliveredAt,
 		delivery.UpdatedAt,
 	)
+	tracing.End(span, err)
 
 	if err != nil {
 		return fmt.Errorf("failed to update webhook delivery: %w", err)
@@ -101,7 +130,7 @@ func (r *webhookDeliveryRepository) FindByID(ctx context.Context, id uuid.UUID)
 	query := `
 		SELECT id, webhook_id, transaction_id, payload, status, http_status_code,
 		       response_body, error_message, retry_count, max_retries, next_retry_at,
-		       delivered_at, created_at, updated_at
+		       delivered_at, created_at, updated_at, trace_parent
 		FROM webhook_deliveries 
 		WHERE id = $1`
 
@@ -116,20 +145,26 @@ func (r *webhookDeliveryRepository) FindByID(ctx context.Context, id uuid.UUID)
 	return &delivery, nil
 }
 
-func (r *webhookDeliveryRepository) FindPendingRetries(ctx context.Context, limit int) ([]*domain.WebhookDelivery, error) {
+// FindPendingRetries locks up to limit deliveries that are due for another
+// attempt: failed ones whose backoff elapsed, and claimed ones whose lease
+// expired without an outcome. Rows locked by another transaction are skipped,
+// so concurrent schedulers never pick the same delivery.
+func (r *webhookDeliveryRepository) FindPendingRetries(ctx context.Context, tx *sqlx.Tx, limit int) ([]*domain.WebhookDelivery, error) {
 	var deliveries []*domain.WebhookDelivery
 	query := `
 		SELECT id, webhook_id, transaction_id, payload, status, http_status_code,
 		       response_body, error_message, retry_count, max_retries, next_retry_at,
-		       delivered_at, created_at, updated_at
+		       delivered_at, created_at, updated_at, trace_parent
 		FROM webhook_deliveries 
-		WHERE status = 'failed' 
-		  AND retry_count < max_retries 
-		  AND (next_retry_at IS NULL OR next_retry_at <= NOW())
-		ORDER BY created_at ASC
-		LIMIT $1`
+		WHERE (status = 'failed'
+		       AND retry_count < max_retries
+		       AND (next_retry_at IS NULL OR next_retry_at <= NOW()))
+		   OR (status = 'pending' AND next_retry_at <= NOW())
+		ORDER BY next_retry_at ASC NULLS FIRST, created_at ASC
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED`
 
-	err := r.db.SelectContext(ctx, &deliveries, query, limit)
+	err := tx.SelectContext(ctx, &deliveries, query, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find pending retries: %w", err)
 	}
@@ -137,12 +172,35 @@ func (r *webhookDeliveryRepository) FindPendingRetries(ctx context.Context, limi
 	return deliveries, nil
 }
 
+// Claim takes a pending delivery for sending by moving its lease from seen to
+// lease. It returns nil when the delivery is no longer pending or its lease
+// changed since seen, i.e. another dispatcher or the retry scheduler owns it.
+func (r *webhookDeliveryRepository) Claim(ctx context.Context, id uuid.UUID, seen *time.Time, lease time.Time) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	query := `
+		UPDATE webhook_deliveries SET next_retry_at = $3, updated_at = NOW()
+		WHERE id = $1 AND status = 'pending' AND next_retry_at IS NOT DISTINCT FROM $2
+		RETURNING id, webhook_id, transaction_id, payload, status, http_status_code,
+		          response_body, error_message, retry_count, max_retries, next_retry_at,
+		          delivered_at, created_at, updated_at, trace_parent`
+
+	err := r.db.GetContext(ctx, &delivery, query, id, seen, lease)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim webhook delivery: %w", err)
+	}
+
+	return &delivery, nil
+}
+
 func (r *webhookDeliveryRepository) FindByWebhookID(ctx context.Context, webhookID uuid.UUID, limit int) ([]*domain.WebhookDelivery, error) {
 	var deliveries []*domain.WebhookDelivery
 	query := `
 		SELECT id, webhook_id, transaction_id, payload, status, http_status_code,
 		       response_body, error_message, retry_count, max_retries, next_retry_at,
-		       delivered_at, created_at, updated_at
+		       delivered_at, created_at, updated_at, trace_parent
 		FROM webhook_deliveries 
 		WHERE webhook_id = $1
 		ORDER BY created_at DESC
@@ -156,3 +214,100 @@ func (r *webhookDeliveryRepository) FindByWebhookID(ctx context.Context, webhook
 	return deliveries, nil
 }
 
+// FindWebhookIDsByTransactionID returns the webhooks that were notified about a transaction
+func (r *webhookDeliveryRepository) FindWebhookIDsByTransactionID(ctx context.Context, tx *sqlx.Tx, transactionID uuid.UUID) ([]uuid.UUID, error) {
+	var webhookIDs []uuid.UUID
+	query := `
+		SELECT DISTINCT webhook_id
+		FROM webhook_deliveries
+		WHERE transaction_id = $1`
+
+	err := tx.SelectContext(ctx, &webhookIDs, query, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find webhook IDs by transaction ID: %w", err)
+	}
+
+	return webhookIDs, nil
+}
+
+// List returns the deliveries of a webhook matching filter, newest first
+func (r *webhookDeliveryRepository) List(ctx context.Context, filter DeliveryFilter) ([]*domain.WebhookDelivery, error) {
+	conditions := []string{"webhook_id = $1"}
+	args := []interface{}{filter.WebhookID}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if len(filter.Statuses) > 0 {
+		conditions = append(conditions, fmt.Sprintf("status = ANY(%s)", arg(pq.StringArray(filter.Statuses))))
+	}
+	if filter.FromTime != nil {
+		conditions = append(conditions, "created_at >= "+arg(*filter.FromTime))
+	}
+	if filter.ToTime != nil {
+		conditions = append(conditions, "created_at <= "+arg(*filter.ToTime))
+	}
+	if filter.After != nil {
+		conditions = append(conditions, fmt.Sprintf("(created_at, id) < (%s, %s)", arg(filter.After.CreatedAt), arg(filter.After.ID)))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, webhook_id, transaction_id, payload, status, http_status_code,
+		       response_body, error_message, retry_count, max_retries, next_retry_at,
+		       delivered_at, created_at, updated_at, trace_parent
+		FROM webhook_deliveries
+		WHERE %s
+		ORDER BY created_at DESC, id DESC
+		LIMIT %s`, strings.Join(conditions, " AND "), arg(filter.Limit))
+
+	var deliveries []*domain.WebhookDelivery
+	if err := r.db.SelectContext(ctx, &deliveries, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// MarkForRedelivery puts a delivery that is not in flight back to pending with
+// a fresh retry budget, with lease as the time the retry scheduler may pick it
+// up if the queued copy is lost. It returns nil when the delivery is already pending.
+func (r *webhookDeliveryRepository) MarkForRedelivery(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, lease time.Time) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	query := `
+		UPDATE webhook_deliveries SET status = 'pending', retry_count = 0, next_retry_at = $2, updated_at = NOW()
+		WHERE id = $1 AND status <> 'pending'
+		RETURNING id, webhook_id, transaction_id, payload, status, http_status_code,
+		          response_body, error_message, retry_count, max_retries, next_retry_at,
+		          delivered_at, created_at, updated_at, trace_parent`
+
+	err := tx.GetContext(ctx, &delivery, query, id, lease)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to mark webhook delivery for redelivery: %w", err)
+	}
+
+	return &delivery, nil
+}
+
+// MarkFailedForRedelivery puts every failed delivery of a webhook created
+// between from and to back to pending, see MarkForRedelivery
+func (r *webhookDeliveryRepository) MarkFailedForRedelivery(ctx context.Context, tx *sqlx.Tx, webhookID uuid.UUID, from, to time.Time, lease time.Time) ([]*domain.WebhookDelivery, error) {
+	var deliveries []*domain.WebhookDelivery
+	query := `
+		UPDATE webhook_deliveries SET status = 'pending', retry_count = 0, next_retry_at = $4, updated_at = NOW()
+		WHERE webhook_id = $1
+		  AND created_at BETWEEN $2 AND $3
+		  AND status IN ('failed', 'max_retries_exceeded')
+		RETURNING id, webhook_id, transaction_id, payload, status, http_status_code,
+		          response_body, error_message, retry_count, max_retries, next_retry_at,
+		          delivered_at, created_at, updated_at, trace_parent`
+
+	if err := tx.SelectContext(ctx, &deliveries, query, webhookID, from, to, lease); err != nil {
+		return nil, fmt.Errorf("failed to mark failed webhook deliveries for redelivery: %w", err)
+	}
+
+	return deliveries, nil
+}
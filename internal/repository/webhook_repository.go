@@ -29,14 +29,16 @@ func NewWebhookRepository(db *sqlx.DB) WebhookRepository {
 
 func (r *webhookRepository) Create(ctx context.Context, tx *sqlx.Tx, webhook *domain.Webhook) (domain.Webhook, error) {
 	query := `
-		INSERT INTO webhooks (id, address_id, url, secret, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)`
+		INSERT INTO webhooks (id, address_id, url, secret, is_active, events, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
 
 	_, err := tx.ExecContext(ctx, query,
 		webhook.ID,
 		webhook.AddressID,
 		webhook.URL,
 		webhook.Secret,
+		webhook.IsActive,
+		webhook.Events,
 		webhook.CreatedAt,
 		webhook.UpdatedAt,
 	)
@@ -53,13 +55,17 @@ func (r *webhookRepository) Update(ctx context.Context, tx *sqlx.Tx, webhook *do
 		UPDATE webhooks SET
 			url = $2,
 			secret = $3,
-			updated_at = $4
+			is_active = $4,
+			events = $5,
+			updated_at = $6
 		WHERE id = $1`
 
 	_, err := tx.ExecContext(ctx, query,
 		webhook.ID,
 		webhook.URL,
 		webhook.Secret,
+		webhook.IsActive,
+		webhook.Events,
 		webhook.UpdatedAt,
 	)
 
@@ -84,7 +90,7 @@ func (r *webhookRepository) Delete(ctx context.Context, tx *sqlx.Tx, id uuid.UUI
 func (r *webhookRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Webhook, error) {
 	var webhook domain.Webhook
 	query := `
-		SELECT id, address_id, url, secret, created_at, updated_at
+		SELECT id, address_id, url, secret, is_active, events, created_at, updated_at
 		FROM webhooks 
 		WHERE id = $1`
 
@@ -102,7 +108,7 @@ func (r *webhookRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain
 func (r *webhookRepository) FindByAddressID(ctx context.Context, addressID uuid.UUID) ([]*domain.Webhook, error) {
 	var webhooks []*domain.Webhook
 	query := `
-		SELECT id, address_id, url, secret, created_at, updated_at
+		SELECT id, address_id, url, secret, is_active, events, created_at, updated_at
 		FROM webhooks 
 		WHERE address_id = $1
 		ORDER BY created_at ASC`
@@ -2,8 +2,10 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"evm-tx-watcher/internal/cache"
 	"evm-tx-watcher/internal/domain"
 	"evm-tx-watcher/internal/dto"
 	"evm-tx-watcher/internal/errors"
@@ -14,21 +16,25 @@ import (
 )
 
 type AddressService interface {
-	Register(ctx context.Context, address *dto.RegisterAddressRequest) (*dto.AddressResponse, *errors.AppError)
-	GetAll(ctx context.Context) ([]*dto.AddressResponse, *errors.AppError)
+	Register(ctx context.Context, userID uuid.UUID, address *dto.RegisterAddressRequest) (*dto.AddressResponse, *errors.AppError)
+	GetAll(ctx context.Context, userID uuid.UUID) ([]*dto.AddressResponse, *errors.AppError)
+	GetByID(ctx context.Context, userID, id uuid.UUID) (*dto.AddressResponse, *errors.AppError)
+	Update(ctx context.Context, userID, id uuid.UUID, request *dto.UpdateAddressRequest) (*dto.AddressResponse, *errors.AppError)
+	Delete(ctx context.Context, userID, id uuid.UUID) *errors.AppError
 }
 
 type addressService struct {
 	unitOfWork  repository.UnitOfWork
 	addressRepo repository.AddressRepository
 	webhookRepo repository.WebhookRepository
+	cache       *cache.RedisClient
 }
 
-func NewAddressService(unitOfWork repository.UnitOfWork, repo repository.AddressRepository, webhookRepo repository.WebhookRepository) AddressService {
-	return &addressService{unitOfWork: unitOfWork, addressRepo: repo, webhookRepo: webhookRepo}
+func NewAddressService(unitOfWork repository.UnitOfWork, repo repository.AddressRepository, webhookRepo repository.WebhookRepository, cache *cache.RedisClient) AddressService {
+	return &addressService{unitOfWork: unitOfWork, addressRepo: repo, webhookRepo: webhookRepo, cache: cache}
 }
-func (s *addressService) Register(ctx context.Context, address *dto.RegisterAddressRequest) (*dto.AddressResponse, *errors.AppError) {
-	existingAddress, err := s.addressRepo.FindByAddress(ctx, address.Address)
+func (s *addressService) Register(ctx context.Context, userID uuid.UUID, address *dto.RegisterAddressRequest) (*dto.AddressResponse, *errors.AppError) {
+	existingAddress, err := s.addressRepo.FindByAddress(ctx, userID, address.Address, address.ChainID)
 	if err != nil {
 		return nil, errors.Wrap(errors.ErrCodeDatabase, "failed to check existing address", err)
 	}
@@ -45,7 +51,7 @@ func (s *addressService) Register(ctx context.Context, address *dto.RegisterAddr
 		IsActive:    true,  // Default to active
 		Label:       address.Label,
 		Description: address.Description,
-		UserID:      nil, // Assuming no user association for now
+		UserID:      &userID,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
@@ -55,6 +61,8 @@ func (s *addressService) Register(ctx context.Context, address *dto.RegisterAddr
 		AddressID: newAddress.ID,
 		URL:       address.WebhookURL,
 		Secret:    address.Secret,
+		IsActive:  true,
+		Events:    domain.AllWebhookEventFilters,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
@@ -78,54 +86,143 @@ func (s *addressService) Register(ctx context.Context, address *dto.RegisterAddr
 		return nil, errors.Wrap(errors.ErrCodeDatabase, "failed to register address", err)
 	}
 
-	// Map UserID to string pointer
-	var userID *string
-	if createdAddress.UserID != nil {
-		s := createdAddress.UserID.String()
-		userID = &s
+	// Let workers pick up the new address
+	if appErr := invalidateWatchedAddresses(ctx, s.cache); appErr != nil {
+		return nil, appErr
 	}
 
-	return &dto.AddressResponse{
-		ID:          createdAddress.ID.String(),
-		Address:     createdAddress.Address,
-		ChainID:     createdAddress.ChainID,
-		IsContract:  createdAddress.IsContract,
-		IsActive:    createdAddress.IsActive,
-		Label:       createdAddress.Label,
-		WebhookURL:  newWebhook.URL,
-		Description: createdAddress.Description,
-		UserID:      userID,
-		CreatedAt:   createdAddress.CreatedAt,
-		UpdatedAt:   createdAddress.UpdatedAt,
-	}, nil
+	return toAddressResponse(&createdAddress, newWebhook.URL), nil
 }
 
-func (s *addressService) GetAll(ctx context.Context) ([]*dto.AddressResponse, *errors.AppError) {
-	addresses, err := s.addressRepo.FindAll(ctx)
+func (s *addressService) GetAll(ctx context.Context, userID uuid.UUID) ([]*dto.AddressResponse, *errors.AppError) {
+	addresses, err := s.addressRepo.FindAll(ctx, userID)
 	if err != nil {
 		return nil, errors.Wrap(errors.ErrCodeDatabase, "failed to get all addresses", err)
 	}
 
 	var responses []*dto.AddressResponse
 	for _, addr := range addresses {
-		var userID *string
-		if addr.UserID != nil {
-			s := addr.UserID.String()
-			userID = &s
-		}
-		responses = append(responses, &dto.AddressResponse{
-			ID:          addr.ID.String(),
-			Address:     addr.Address,
-			ChainID:     addr.ChainID,
-			IsContract:  addr.IsContract,
-			IsActive:    addr.IsActive,
-			Label:       addr.Label,
-			Description: addr.Description,
-			UserID:      userID,
-			CreatedAt:   addr.CreatedAt,
-			UpdatedAt:   addr.UpdatedAt,
-		})
+		responses = append(responses, toAddressResponse(addr, ""))
 	}
 
 	return responses, nil
 }
+
+func (s *addressService) GetByID(ctx context.Context, userID, id uuid.UUID) (*dto.AddressResponse, *errors.AppError) {
+	addr, appErr := findAddress(ctx, s.addressRepo, userID, id)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	return s.withWebhookURL(ctx, addr)
+}
+
+func (s *addressService) Update(ctx context.Context, userID, id uuid.UUID, request *dto.UpdateAddressRequest) (*dto.AddressResponse, *errors.AppError) {
+	addr, appErr := findAddress(ctx, s.addressRepo, userID, id)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if request.Label != nil {
+		addr.Label = request.Label
+	}
+	if request.Description != nil {
+		addr.Description = request.Description
+	}
+	if request.IsActive != nil {
+		addr.IsActive = *request.IsActive
+	}
+	addr.UpdatedAt = time.Now()
+
+	err := s.unitOfWork.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		return s.addressRepo.Update(ctx, tx, addr)
+	})
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeDatabase, "failed to update address", err)
+	}
+
+	// Activation changes must reach the workers' watched set immediately
+	if appErr := invalidateWatchedAddresses(ctx, s.cache); appErr != nil {
+		return nil, appErr
+	}
+
+	return s.withWebhookURL(ctx, addr)
+}
+
+func (s *addressService) Delete(ctx context.Context, userID, id uuid.UUID) *errors.AppError {
+	if _, appErr := findAddress(ctx, s.addressRepo, userID, id); appErr != nil {
+		return appErr
+	}
+
+	err := s.unitOfWork.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		return s.addressRepo.Delete(ctx, tx, id)
+	})
+	if err != nil {
+		return errors.Wrap(errors.ErrCodeDatabase, "failed to delete address", err)
+	}
+
+	// Stop the workers from matching the deleted address
+	return invalidateWatchedAddresses(ctx, s.cache)
+}
+
+// invalidateWatchedAddresses makes the workers reload their watched set after a
+// committed change. The change is stored either way, but when the cache cannot
+// be cleared the workers only see it once the cached list expires, so the
+// request fails to tell the client.
+func invalidateWatchedAddresses(ctx context.Context, redis *cache.RedisClient) *errors.AppError {
+	if err := redis.InvalidateWatchedAddresses(ctx); err != nil {
+		return errors.InternalError(fmt.Sprintf("Change saved, but workers may take up to %s to apply it",
+			cache.WatchedAddressesTTL), err)
+	}
+	return nil
+}
+
+// findAddress loads an address of the user or returns a not found error, so
+// other tenants' addresses are indistinguishable from missing ones
+func findAddress(ctx context.Context, addressRepo repository.AddressRepository, userID, id uuid.UUID) (*domain.Address, *errors.AppError) {
+	addr, err := addressRepo.FindByID(ctx, userID, id)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeDatabase, "failed to get address", err)
+	}
+	if addr == nil {
+		return nil, errors.NotFound("Address")
+	}
+	return addr, nil
+}
+
+// withWebhookURL maps an address to its response, including the URL of its first webhook
+func (s *addressService) withWebhookURL(ctx context.Context, addr *domain.Address) (*dto.AddressResponse, *errors.AppError) {
+	webhooks, err := s.webhookRepo.FindByAddressID(ctx, addr.ID)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeDatabase, "failed to get address webhooks", err)
+	}
+
+	var webhookURL string
+	if len(webhooks) > 0 {
+		webhookURL = webhooks[0].URL
+	}
+	return toAddressResponse(addr, webhookURL), nil
+}
+
+func toAddressResponse(addr *domain.Address, webhookURL string) *dto.AddressResponse {
+	// Map UserID to string pointer
+	var userID *string
+	if addr.UserID != nil {
+		s := addr.UserID.String()
+		userID = &s
+	}
+
+	return &dto.AddressResponse{
+		ID:          addr.ID.String(),
+		Address:     addr.Address,
+		ChainID:     addr.ChainID,
+		IsContract:  addr.IsContract,
+		IsActive:    addr.IsActive,
+		Label:       addr.Label,
+		WebhookURL:  webhookURL,
+		Description: addr.Description,
+		UserID:      userID,
+		CreatedAt:   addr.CreatedAt,
+		UpdatedAt:   addr.UpdatedAt,
+	}
+}
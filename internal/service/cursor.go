@@ -0,0 +1,21 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// encodePageCursor turns a keyset position into the opaque cursor handed to clients
+func encodePageCursor(position interface{}) string {
+	data, _ := json.Marshal(position)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodePageCursor reads a cursor produced by encodePageCursor into position
+func decodePageCursor(cursor string, position interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, position)
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"evm-tx-watcher/internal/cache"
+	"evm-tx-watcher/internal/domain"
+	"evm-tx-watcher/internal/dto"
+	"evm-tx-watcher/internal/errors"
+	"evm-tx-watcher/internal/repository"
+	"evm-tx-watcher/internal/util"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	// defaultDeliveryPageSize is used when a list request sets no limit
+	defaultDeliveryPageSize = 50
+	// redeliveryLease matches the dispatcher's retry lease: a redelivery whose
+	// queued copy is lost is picked up by the retry scheduler after this long
+	redeliveryLease = 5 * time.Minute
+)
+
+type DeliveryService interface {
+	List(ctx context.Context, userID, addressID, webhookID uuid.UUID, request *dto.ListDeliveriesRequest) (*dto.DeliveryListResponse, *errors.AppError)
+	Get(ctx context.Context, userID, addressID, webhookID, id uuid.UUID) (*dto.DeliveryResponse, *errors.AppError)
+	Redeliver(ctx context.Context, userID, addressID, webhookID, id uuid.UUID) (*dto.DeliveryResponse, *errors.AppError)
+	RedeliverFailed(ctx context.Context, userID, addressID, webhookID uuid.UUID, request *dto.RedeliverFailedRequest) (*dto.RedeliveryResponse, *errors.AppError)
+}
+
+type deliveryService struct {
+	unitOfWork   repository.UnitOfWork
+	addressRepo  repository.AddressRepository
+	webhookRepo  repository.WebhookRepository
+	deliveryRepo repository.WebhookDeliveryRepository
+	attemptRepo  repository.WebhookDeliveryAttemptRepository
+	cache        *cache.RedisClient
+	logger       *util.Logger
+}
+
+func NewDeliveryService(
+	unitOfWork repository.UnitOfWork,
+	addressRepo repository.AddressRepository,
+	webhookRepo repository.WebhookRepository,
+	deliveryRepo repository.WebhookDeliveryRepository,
+	attemptRepo repository.WebhookDeliveryAttemptRepository,
+	cache *cache.RedisClient,
+	logger *util.Logger,
+) DeliveryService {
+	return &deliveryService{
+		unitOfWork:   unitOfWork,
+		addressRepo:  addressRepo,
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+		attemptRepo:  attemptRepo,
+		cache:        cache,
+		logger:       logger,
+	}
+}
+
+// deliveryCursor is the keyset position behind a delivery page cursor
+type deliveryCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uuid.UUID `json:"id"`
+}
+
+func (s *deliveryService) List(ctx context.Context, userID, addressID, webhookID uuid.UUID, request *dto.ListDeliveriesRequest) (*dto.DeliveryListResponse, *errors.AppError) {
+	if _, appErr := findAddressWebhook(ctx, s.addressRepo, s.webhookRepo, userID, addressID, webhookID); appErr != nil {
+		return nil, appErr
+	}
+
+	filter := repository.DeliveryFilter{
+		WebhookID: webhookID,
+		Statuses:  request.Status,
+		Limit:     request.Limit,
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultDeliveryPageSize
+	}
+	var appErr *errors.AppError
+	if filter.FromTime, appErr = parseTime(request.FromTime, "from_time"); appErr != nil {
+		return nil, appErr
+	}
+	if filter.ToTime, appErr = parseTime(request.ToTime, "to_time"); appErr != nil {
+		return nil, appErr
+	}
+	if request.Cursor != "" {
+		var cursor deliveryCursor
+		if err := decodePageCursor(request.Cursor, &cursor); err != nil {
+			return nil, errors.ValidationError("Invalid cursor")
+		}
+		filter.After = &repository.DeliveryCursor{CreatedAt: cursor.CreatedAt, ID: cursor.ID}
+	}
+
+	// Fetch one extra row to learn whether another page follows
+	filter.Limit++
+	deliveries, err := s.deliveryRepo.List(ctx, filter)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeDatabase, "failed to list deliveries", err)
+	}
+
+	response := &dto.DeliveryListResponse{Deliveries: make([]*dto.DeliveryResponse, 0, len(deliveries))}
+	if len(deliveries) == filter.Limit {
+		deliveries = deliveries[:filter.Limit-1]
+		last := deliveries[len(deliveries)-1]
+		next := encodePageCursor(deliveryCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+		response.NextCursor = &next
+	}
+	for _, delivery := range deliveries {
+		response.Deliveries = append(response.Deliveries, toDeliveryResponse(delivery))
+	}
+	return response, nil
+}
+
+func (s *deliveryService) Get(ctx context.Context, userID, addressID, webhookID, id uuid.UUID) (*dto.DeliveryResponse, *errors.AppError) {
+	delivery, appErr := s.findDelivery(ctx, userID, addressID, webhookID, id)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	attempts, err := s.attemptRepo.FindByDeliveryID(ctx, delivery.ID)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeDatabase, "failed to get delivery attempts", err)
+	}
+
+	response := toDeliveryResponse(delivery)
+	response.Payload = json.RawMessage(delivery.Payload)
+	response.Attempts = attempts
+	return response, nil
+}
+
+func (s *deliveryService) Redeliver(ctx context.Context, userID, addressID, webhookID, id uuid.UUID) (*dto.DeliveryResponse, *errors.AppError) {
+	if _, appErr := s.findDelivery(ctx, userID, addressID, webhookID, id); appErr != nil {
+		return nil, appErr
+	}
+
+	var marked *domain.WebhookDelivery
+	err := s.unitOfWork.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		var err error
+		marked, err = s.deliveryRepo.MarkForRedelivery(ctx, tx, id, time.Now().Add(redeliveryLease))
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeDatabase, "failed to schedule redelivery", err)
+	}
+	if marked == nil {
+		return nil, errors.New(errors.ErrCodeBadRequest, "Delivery is already pending")
+	}
+
+	s.enqueue(ctx, marked)
+	return toDeliveryResponse(marked), nil
+}
+
+func (s *deliveryService) RedeliverFailed(ctx context.Context, userID, addressID, webhookID uuid.UUID, request *dto.RedeliverFailedRequest) (*dto.RedeliveryResponse, *errors.AppError) {
+	if _, appErr := findAddressWebhook(ctx, s.addressRepo, s.webhookRepo, userID, addressID, webhookID); appErr != nil {
+		return nil, appErr
+	}
+
+	from, appErr := parseTime(&request.FromTime, "from_time")
+	if appErr != nil {
+		return nil, appErr
+	}
+	to, appErr := parseTime(&request.ToTime, "to_time")
+	if appErr != nil {
+		return nil, appErr
+	}
+	if to.Before(*from) {
+		return nil, errors.ValidationError("to_time must not be before from_time")
+	}
+
+	var marked []*domain.WebhookDelivery
+	err := s.unitOfWork.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		var err error
+		marked, err = s.deliveryRepo.MarkFailedForRedelivery(ctx, tx, webhookID, *from, *to, time.Now().Add(redeliveryLease))
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeDatabase, "failed to schedule redeliveries", err)
+	}
+
+	response := &dto.RedeliveryResponse{Count: len(marked), DeliveryIDs: make([]string, 0, len(marked))}
+	for _, delivery := range marked {
+		s.enqueue(ctx, delivery)
+		response.DeliveryIDs = append(response.DeliveryIDs, delivery.ID.String())
+	}
+	return response, nil
+}
+
+// findDelivery loads a delivery of the user's webhook or returns a not found error
+func (s *deliveryService) findDelivery(ctx context.Context, userID, addressID, webhookID, id uuid.UUID) (*domain.WebhookDelivery, *errors.AppError) {
+	if _, appErr := findAddressWebhook(ctx, s.addressRepo, s.webhookRepo, userID, addressID, webhookID); appErr != nil {
+		return nil, appErr
+	}
+
+	delivery, err := s.deliveryRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeDatabase, "failed to get delivery", err)
+	}
+	if delivery == nil || delivery.WebhookID != webhookID {
+		return nil, errors.NotFound("Delivery")
+	}
+	return delivery, nil
+}
+
+// enqueue hands a redelivery to the dispatcher; if this fails the retry
+// scheduler sends it once the lease expires
+func (s *deliveryService) enqueue(ctx context.Context, delivery *domain.WebhookDelivery) {
+	if err := s.cache.QueueWebhookDelivery(ctx, delivery.ID, *delivery.NextRetryAt); err != nil {
+		s.logger.WithError(err).Errorf("Failed to queue redelivery %s, it is sent once its lease expires", delivery.ID)
+	}
+}
+
+func toDeliveryResponse(delivery *domain.WebhookDelivery) *dto.DeliveryResponse {
+	var payload struct {
+		Event string `json:"event"`
+	}
+	_ = json.Unmarshal([]byte(delivery.Payload), &payload)
+
+	return &dto.DeliveryResponse{
+		ID:             delivery.ID.String(),
+		WebhookID:      delivery.WebhookID.String(),
+		TransactionID:  delivery.TransactionID.String(),
+		Event:          payload.Event,
+		Status:         delivery.Status,
+		HTTPStatusCode: delivery.HTTPStatusCode,
+		ResponseBody:   delivery.ResponseBody,
+		ErrorMessage:   delivery.ErrorMessage,
+		RetryCount:     delivery.RetryCount,
+		MaxRetries:     delivery.MaxRetries,
+		NextRetryAt:    delivery.NextRetryAt,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+		UpdatedAt:      delivery.UpdatedAt,
+	}
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"evm-tx-watcher/internal/domain"
+	"evm-tx-watcher/internal/dto"
+	"evm-tx-watcher/internal/errors"
+	"evm-tx-watcher/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// defaultTransactionPageSize is used when a list request sets no limit
+const defaultTransactionPageSize = 50
+
+type TransactionService interface {
+	List(ctx context.Context, userID uuid.UUID, request *dto.ListTransactionsRequest) (*dto.TransactionListResponse, *errors.AppError)
+	GetByHash(ctx context.Context, userID uuid.UUID, hash string) (*domain.Transaction, *errors.AppError)
+}
+
+type transactionService struct {
+	txRepo       repository.TransactionRepository
+	transferRepo repository.TokenTransferRepository
+	internalRepo repository.InternalTransferRepository
+}
+
+func NewTransactionService(txRepo repository.TransactionRepository, transferRepo repository.TokenTransferRepository, internalRepo repository.InternalTransferRepository) TransactionService {
+	return &transactionService{txRepo: txRepo, transferRepo: transferRepo, internalRepo: internalRepo}
+}
+
+// pageCursor is the opaque cursor handed to clients
+type pageCursor struct {
+	BlockNumber      int64     `json:"b"`
+	TransactionIndex int       `json:"i"`
+	ID               uuid.UUID `json:"id"`
+}
+
+func (s *transactionService) List(ctx context.Context, userID uuid.UUID, request *dto.ListTransactionsRequest) (*dto.TransactionListResponse, *errors.AppError) {
+	filter := repository.TransactionFilter{
+		UserID:         &userID,
+		ChainID:        request.ChainID,
+		FromBlock:      request.FromBlock,
+		ToBlock:        request.ToBlock,
+		Status:         request.Status,
+		IncludeRemoved: request.IncludeRemoved,
+		Limit:          request.Limit,
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultTransactionPageSize
+	}
+	if request.Address != nil {
+		address := strings.ToLower(*request.Address)
+		filter.Address = &address
+	}
+	if request.TokenAddress != nil {
+		tokenAddress := strings.ToLower(*request.TokenAddress)
+		filter.TokenAddress = &tokenAddress
+	}
+	var appErr *errors.AppError
+	if filter.FromTime, appErr = parseTime(request.FromTime, "from_time"); appErr != nil {
+		return nil, appErr
+	}
+	if filter.ToTime, appErr = parseTime(request.ToTime, "to_time"); appErr != nil {
+		return nil, appErr
+	}
+	if request.Cursor != "" {
+		cursor, err := decodeCursor(request.Cursor)
+		if err != nil {
+			return nil, errors.ValidationError("Invalid cursor")
+		}
+		filter.After = cursor
+	}
+
+	// Fetch one extra row to learn whether another page follows
+	filter.Limit++
+	transactions, err := s.txRepo.List(ctx, filter)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeDatabase, "failed to list transactions", err)
+	}
+
+	response := &dto.TransactionListResponse{Transactions: transactions}
+	if len(transactions) == filter.Limit {
+		response.Transactions = transactions[:filter.Limit-1]
+		last := response.Transactions[len(response.Transactions)-1]
+		next := encodeCursor(last)
+		response.NextCursor = &next
+	}
+
+	if appErr := s.attachTransfers(ctx, response.Transactions); appErr != nil {
+		return nil, appErr
+	}
+	return response, nil
+}
+
+func (s *transactionService) GetByHash(ctx context.Context, userID uuid.UUID, hash string) (*domain.Transaction, *errors.AppError) {
+	transaction, err := s.txRepo.FindByHash(ctx, strings.ToLower(hash))
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeDatabase, "failed to get transaction", err)
+	}
+	if transaction == nil {
+		return nil, errors.NotFound("Transaction")
+	}
+
+	// Transactions of other tenants' addresses are reported as missing
+	involved, err := s.txRepo.InvolvesUser(ctx, transaction.ID, userID)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeDatabase, "failed to get transaction", err)
+	}
+	if !involved {
+		return nil, errors.NotFound("Transaction")
+	}
+
+	if appErr := s.attachTransfers(ctx, []*domain.Transaction{transaction}); appErr != nil {
+		return nil, appErr
+	}
+	return transaction, nil
+}
+
+// attachTransfers embeds the token and internal transfers of transactions
+func (s *transactionService) attachTransfers(ctx context.Context, transactions []*domain.Transaction) *errors.AppError {
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	byID := make(map[uuid.UUID]*domain.Transaction, len(transactions))
+	ids := make([]uuid.UUID, 0, len(transactions))
+	for _, transaction := range transactions {
+		byID[transaction.ID] = transaction
+		ids = append(ids, transaction.ID)
+	}
+
+	transfers, err := s.transferRepo.FindByTransactionIDs(ctx, ids)
+	if err != nil {
+		return errors.Wrap(errors.ErrCodeDatabase, "failed to get token transfers", err)
+	}
+	for _, transfer := range transfers {
+		transaction := byID[transfer.TransactionID]
+		transaction.TokenTransfers = append(transaction.TokenTransfers, *transfer)
+	}
+
+	internal, err := s.internalRepo.FindByTransactionIDs(ctx, ids)
+	if err != nil {
+		return errors.Wrap(errors.ErrCodeDatabase, "failed to get internal transfers", err)
+	}
+	for _, transfer := range internal {
+		transaction := byID[transfer.TransactionID]
+		transaction.InternalTransfers = append(transaction.InternalTransfers, *transfer)
+	}
+	return nil
+}
+
+func parseTime(value *string, field string) (*time.Time, *errors.AppError) {
+	if value == nil {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, *value)
+	if err != nil {
+		return nil, errors.ValidationError(field + " must be an RFC 3339 timestamp")
+	}
+	return &t, nil
+}
+
+func encodeCursor(transaction *domain.Transaction) string {
+	return encodePageCursor(pageCursor{
+		BlockNumber:      transaction.BlockNumber,
+		TransactionIndex: transaction.TransactionIndex,
+		ID:               transaction.ID,
+	})
+}
+
+func decodeCursor(value string) (*repository.TransactionCursor, error) {
+	var cursor pageCursor
+	if err := decodePageCursor(value, &cursor); err != nil {
+		return nil, err
+	}
+	return &repository.TransactionCursor{
+		BlockNumber:      cursor.BlockNumber,
+		TransactionIndex: cursor.TransactionIndex,
+		ID:               cursor.ID,
+	}, nil
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"evm-tx-watcher/internal/cache"
+	"evm-tx-watcher/internal/domain"
+	"evm-tx-watcher/internal/dto"
+	"evm-tx-watcher/internal/errors"
+	"evm-tx-watcher/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type WebhookService interface {
+	List(ctx context.Context, userID, addressID uuid.UUID) ([]*dto.WebhookResponse, *errors.AppError)
+	Create(ctx context.Context, userID, addressID uuid.UUID, request *dto.CreateWebhookRequest) (*dto.WebhookResponse, *errors.AppError)
+	Update(ctx context.Context, userID, addressID, id uuid.UUID, request *dto.UpdateWebhookRequest) (*dto.WebhookResponse, *errors.AppError)
+	Delete(ctx context.Context, userID, addressID, id uuid.UUID) *errors.AppError
+}
+
+type webhookService struct {
+	unitOfWork  repository.UnitOfWork
+	addressRepo repository.AddressRepository
+	webhookRepo repository.WebhookRepository
+	cache       *cache.RedisClient
+}
+
+func NewWebhookService(unitOfWork repository.UnitOfWork, addressRepo repository.AddressRepository, webhookRepo repository.WebhookRepository, cache *cache.RedisClient) WebhookService {
+	return &webhookService{unitOfWork: unitOfWork, addressRepo: addressRepo, webhookRepo: webhookRepo, cache: cache}
+}
+
+func (s *webhookService) List(ctx context.Context, userID, addressID uuid.UUID) ([]*dto.WebhookResponse, *errors.AppError) {
+	if _, appErr := findAddress(ctx, s.addressRepo, userID, addressID); appErr != nil {
+		return nil, appErr
+	}
+
+	webhooks, err := s.webhookRepo.FindByAddressID(ctx, addressID)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeDatabase, "failed to get webhooks", err)
+	}
+
+	responses := make([]*dto.WebhookResponse, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		responses = append(responses, toWebhookResponse(webhook))
+	}
+	return responses, nil
+}
+
+func (s *webhookService) Create(ctx context.Context, userID, addressID uuid.UUID, request *dto.CreateWebhookRequest) (*dto.WebhookResponse, *errors.AppError) {
+	if _, appErr := findAddress(ctx, s.addressRepo, userID, addressID); appErr != nil {
+		return nil, appErr
+	}
+
+	events := request.Events
+	if len(events) == 0 {
+		events = domain.AllWebhookEventFilters
+	}
+	isActive := true
+	if request.IsActive != nil {
+		isActive = *request.IsActive
+	}
+
+	webhook := &domain.Webhook{
+		ID:        uuid.New(),
+		AddressID: addressID,
+		URL:       request.URL,
+		Secret:    request.Secret,
+		IsActive:  isActive,
+		Events:    events,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	err := s.unitOfWork.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		_, err := s.webhookRepo.Create(ctx, tx, webhook)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeDatabase, "failed to create webhook", err)
+	}
+
+	// Let workers pick up the new webhook
+	if appErr := invalidateWatchedAddresses(ctx, s.cache); appErr != nil {
+		return nil, appErr
+	}
+
+	return toWebhookResponse(webhook), nil
+}
+
+func (s *webhookService) Update(ctx context.Context, userID, addressID, id uuid.UUID, request *dto.UpdateWebhookRequest) (*dto.WebhookResponse, *errors.AppError) {
+	webhook, appErr := findAddressWebhook(ctx, s.addressRepo, s.webhookRepo, userID, addressID, id)
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	if request.URL != nil {
+		webhook.URL = *request.URL
+	}
+	if request.Secret != nil {
+		webhook.Secret = *request.Secret
+	}
+	if request.Events != nil {
+		webhook.Events = request.Events
+	}
+	if request.IsActive != nil {
+		webhook.IsActive = *request.IsActive
+	}
+	webhook.UpdatedAt = time.Now()
+
+	err := s.unitOfWork.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		return s.webhookRepo.Update(ctx, tx, webhook)
+	})
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeDatabase, "failed to update webhook", err)
+	}
+
+	// Filters and the active flag are part of the workers' watched set
+	if appErr := invalidateWatchedAddresses(ctx, s.cache); appErr != nil {
+		return nil, appErr
+	}
+
+	return toWebhookResponse(webhook), nil
+}
+
+func (s *webhookService) Delete(ctx context.Context, userID, addressID, id uuid.UUID) *errors.AppError {
+	if _, appErr := findAddressWebhook(ctx, s.addressRepo, s.webhookRepo, userID, addressID, id); appErr != nil {
+		return appErr
+	}
+
+	err := s.unitOfWork.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		return s.webhookRepo.Delete(ctx, tx, id)
+	})
+	if err != nil {
+		return errors.Wrap(errors.ErrCodeDatabase, "failed to delete webhook", err)
+	}
+
+	return invalidateWatchedAddresses(ctx, s.cache)
+}
+
+// findAddressWebhook loads a webhook of one of the user's addresses or returns a not found error
+func findAddressWebhook(ctx context.Context, addressRepo repository.AddressRepository, webhookRepo repository.WebhookRepository, userID, addressID, id uuid.UUID) (*domain.Webhook, *errors.AppError) {
+	if _, appErr := findAddress(ctx, addressRepo, userID, addressID); appErr != nil {
+		return nil, appErr
+	}
+
+	webhook, err := webhookRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(errors.ErrCodeDatabase, "failed to get webhook", err)
+	}
+	if webhook == nil || webhook.AddressID != addressID {
+		return nil, errors.NotFound("Webhook")
+	}
+	return webhook, nil
+}
+
+func toWebhookResponse(webhook *domain.Webhook) *dto.WebhookResponse {
+	return &dto.WebhookResponse{
+		ID:        webhook.ID.String(),
+		AddressID: webhook.AddressID.String(),
+		URL:       webhook.URL,
+		IsActive:  webhook.IsActive,
+		Events:    webhook.Events,
+		CreatedAt: webhook.CreatedAt,
+		UpdatedAt: webhook.UpdatedAt,
+	}
+}
@@ -0,0 +1,117 @@
+package stream
+
+import (
+	"strconv"
+	"strings"
+
+	"evm-tx-watcher/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// Filter selects the events a stream client receives. Empty sets do not filter.
+type Filter struct {
+	UserID    uuid.UUID
+	ChainIDs  map[int64]bool
+	Addresses map[string]bool // lower case; matches either side of the transaction or of a transfer
+	Types     []string        // exact event types, or families such as "transaction"
+}
+
+// Match reports whether the client may see the event and asked for it
+func (f *Filter) Match(envelope *Envelope) bool {
+	if len(envelope.Users) > 0 && !containsUser(envelope.Users, f.UserID) {
+		return false
+	}
+
+	event := envelope.Event
+	if len(f.ChainIDs) > 0 && !f.ChainIDs[event.ChainID] {
+		return false
+	}
+	if len(f.Types) > 0 && !matchesType(f.Types, event.Type) {
+		return false
+	}
+	if len(f.Addresses) > 0 && !f.touches(event) {
+		return false
+	}
+	return true
+}
+
+// touches reports whether one of the filter's addresses is a party to the
+// event; chain-wide notices have no parties
+func (f *Filter) touches(event *domain.StreamEvent) bool {
+	var parties []string
+	if tx := event.Transaction; tx != nil {
+		parties = append(parties, tx.FromAddress)
+		if tx.ToAddress != nil {
+			parties = append(parties, *tx.ToAddress)
+		}
+		for _, transfer := range tx.TokenTransfers {
+			parties = append(parties, transfer.FromAddress, transfer.ToAddress)
+		}
+		for _, transfer := range tx.InternalTransfers {
+			parties = append(parties, transfer.FromAddress, transfer.ToAddress)
+		}
+	}
+	if transfer := event.TokenTransfer; transfer != nil {
+		parties = append(parties, transfer.FromAddress, transfer.ToAddress)
+	}
+
+	for _, party := range parties {
+		if f.Addresses[strings.ToLower(party)] {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesType(types []string, eventType string) bool {
+	family, _, _ := strings.Cut(eventType, ".")
+	for _, t := range types {
+		if t == eventType || t == family {
+			return true
+		}
+	}
+	return false
+}
+
+func containsUser(users []uuid.UUID, userID uuid.UUID) bool {
+	for _, user := range users {
+		if user == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEventID reports whether id has the form of a stream event ID
+func IsEventID(id string) bool {
+	_, _, ok := parseEventID(id)
+	return ok
+}
+
+// eventIDAfter reports whether stream ID a is newer than b
+func eventIDAfter(a, b string) bool {
+	aMs, aSeq, _ := parseEventID(a)
+	bMs, bSeq, _ := parseEventID(b)
+	if aMs != bMs {
+		return aMs > bMs
+	}
+	return aSeq > bSeq
+}
+
+// parseEventID splits a Redis stream ID of the form <milliseconds>-<sequence>
+func parseEventID(id string) (uint64, uint64, bool) {
+	msPart, seqPart, ok := strings.Cut(id, "-")
+	if !ok {
+		return 0, 0, false
+	}
+	ms, err := strconv.ParseUint(msPart, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	seq, err := strconv.ParseUint(seqPart, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return ms, seq, true
+}
@@ -0,0 +1,229 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"evm-tx-watcher/internal/cache"
+	"evm-tx-watcher/internal/domain"
+	"evm-tx-watcher/internal/util"
+)
+
+const (
+	// subscriberBuffer is how many events a client may fall behind before it is
+	// disconnected; it can resume from its last event ID
+	subscriberBuffer = 256
+	// replayPageSize bounds each history read when a client resumes
+	replayPageSize = 500
+	// heartbeatInterval keeps idle connections from being closed by proxies
+	heartbeatInterval = 15 * time.Second
+)
+
+var (
+	// ErrSlowClient ends a stream whose client could not keep up; it can resume
+	// from the last event ID it received
+	ErrSlowClient = errors.New("stream client fell behind")
+	// ErrHubStopped ends every stream when the API shuts down; clients resume
+	// from their last event ID on another replica
+	ErrHubStopped = errors.New("stream hub stopped")
+
+	errMissingEvent = errors.New("stream message without event")
+)
+
+// Sink writes to one stream client
+type Sink interface {
+	Send(event *domain.StreamEvent) error
+	Ping() error
+}
+
+// Subscription receives the live events matching its filter. Events is
+// closed when the client falls too far behind.
+type Subscription struct {
+	filter Filter
+	events chan *domain.StreamEvent
+}
+
+func (s *Subscription) Events() <-chan *domain.StreamEvent {
+	return s.events
+}
+
+// Hub fans the live Redis channel out to the stream clients of one API replica
+type Hub struct {
+	redis *cache.RedisClient
+	log   *util.Logger
+
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+	done        chan struct{} // closed when Run returns
+}
+
+func NewHub(redis *cache.RedisClient, log *util.Logger) *Hub {
+	return &Hub{
+		redis:       redis,
+		log:         log,
+		subscribers: make(map[*Subscription]struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// Run forwards live events to subscribers until ctx is cancelled, then ends
+// every stream
+func (h *Hub) Run(ctx context.Context) {
+	defer close(h.done)
+
+	pubsub := h.redis.SubscribeStreamEvents(ctx)
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			message, err := cache.ParseStreamMessage(msg.Payload)
+			if err != nil {
+				h.log.WithError(err).Warn("[Stream] dropped live event")
+				continue
+			}
+			envelope, err := decode(message)
+			if err != nil {
+				h.log.WithError(err).Warn("[Stream] dropped live event")
+				continue
+			}
+			h.broadcast(envelope)
+		}
+	}
+}
+
+// Subscribe registers a client for live events
+func (h *Hub) Subscribe(filter Filter) *Subscription {
+	sub := &Subscription{filter: filter, events: make(chan *domain.StreamEvent, subscriberBuffer)}
+
+	h.mu.Lock()
+	h.subscribers[sub] = struct{}{}
+	h.mu.Unlock()
+
+	return sub
+}
+
+// Unsubscribe removes a client; it is safe to call after the hub dropped it
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.subscribers[sub]; ok {
+		delete(h.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+func (h *Hub) broadcast(envelope *Envelope) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subscribers {
+		if !sub.filter.Match(envelope) {
+			continue
+		}
+		select {
+		case sub.events <- envelope.Event:
+		default:
+			// A slow client must not hold up the others
+			delete(h.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// Replay returns the recorded events after afterID that match filter, oldest
+// first. History is bounded, so a client away for long may have missed events.
+func (h *Hub) Replay(ctx context.Context, afterID string, filter Filter) ([]*domain.StreamEvent, error) {
+	var events []*domain.StreamEvent
+	for {
+		messages, err := h.redis.StreamEventsAfter(ctx, afterID, replayPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, message := range messages {
+			envelope, err := decode(message)
+			if err != nil {
+				continue
+			}
+			if filter.Match(envelope) {
+				events = append(events, envelope.Event)
+			}
+		}
+		if len(messages) < replayPageSize {
+			return events, nil
+		}
+		afterID = messages[len(messages)-1].ID
+	}
+}
+
+// Follow replays the events after lastEventID, if set, and then passes live
+// events to sink until ctx is cancelled, the hub stops, the client falls
+// behind or a write fails. Live events already replayed are skipped, so none is sent twice.
+func (h *Hub) Follow(ctx context.Context, filter Filter, lastEventID string, sink Sink) error {
+	// Subscribe before reading history so nothing published in between is lost
+	sub := h.Subscribe(filter)
+	defer h.Unsubscribe(sub)
+
+	last := lastEventID
+	if lastEventID != "" {
+		replayed, err := h.Replay(ctx, lastEventID, filter)
+		if err != nil {
+			return err
+		}
+		for _, event := range replayed {
+			if err := sink.Send(event); err != nil {
+				return err
+			}
+			last = event.ID
+		}
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-h.done:
+			return ErrHubStopped
+		case <-heartbeat.C:
+			if err := sink.Ping(); err != nil {
+				return err
+			}
+		case event, ok := <-sub.Events():
+			if !ok {
+				return ErrSlowClient
+			}
+			if last != "" && !eventIDAfter(event.ID, last) {
+				continue
+			}
+			if err := sink.Send(event); err != nil {
+				return err
+			}
+			last = event.ID
+		}
+	}
+}
+
+func decode(message cache.StreamMessage) (*Envelope, error) {
+	var envelope Envelope
+	if err := json.Unmarshal([]byte(message.Data), &envelope); err != nil {
+		return nil, err
+	}
+	if envelope.Event == nil {
+		return nil, errMissingEvent
+	}
+	envelope.Event.ID = message.ID
+	return &envelope, nil
+}
@@ -0,0 +1,46 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+
+	"evm-tx-watcher/internal/cache"
+	"evm-tx-watcher/internal/domain"
+	"evm-tx-watcher/internal/util"
+
+	"github.com/google/uuid"
+)
+
+// Envelope is an event as it travels through Redis, together with the users
+// whose addresses it concerns. Events without users, like reorg notices, are
+// chain-wide and visible to everyone.
+type Envelope struct {
+	Users []uuid.UUID         `json:"users,omitempty"`
+	Event *domain.StreamEvent `json:"event"`
+}
+
+// Publisher hands persisted events to the API replicas serving streams
+type Publisher struct {
+	redis       *cache.RedisClient
+	historySize int64
+	log         *util.Logger
+}
+
+func NewPublisher(redis *cache.RedisClient, historySize int64, log *util.Logger) *Publisher {
+	return &Publisher{redis: redis, historySize: historySize, log: log}
+}
+
+// Publish records and broadcasts envelopes in order. Streams are best effort,
+// webhooks remain the reliable channel, so failures are only logged.
+func (p *Publisher) Publish(ctx context.Context, envelopes []*Envelope) {
+	for _, envelope := range envelopes {
+		data, err := json.Marshal(envelope)
+		if err != nil {
+			p.log.WithError(err).Errorf("[Stream] failed to marshal %s event", envelope.Event.Type)
+			continue
+		}
+		if _, err := p.redis.AppendStreamEvent(ctx, p.historySize, data); err != nil {
+			p.log.WithError(err).Errorf("[Stream] failed to publish %s event", envelope.Event.Type)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+// Package token resolves and caches the metadata of token contracts.
+package token
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"evm-tx-watcher/internal/blockchain/client"
+	"evm-tx-watcher/internal/cache"
+	"evm-tx-watcher/internal/domain"
+	"evm-tx-watcher/internal/repository"
+	"evm-tx-watcher/internal/util"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/jmoiron/sqlx"
+)
+
+// maxTextLength caps symbols and names taken from untrusted contracts
+const maxTextLength = 128
+
+var (
+	decimalsSelector = crypto.Keccak256([]byte("decimals()"))[:4]
+	symbolSelector   = crypto.Keccak256([]byte("symbol()"))[:4]
+	nameSelector     = crypto.Keccak256([]byte("name()"))[:4]
+
+	stringResult = func() abi.Arguments {
+		stringType, _ := abi.NewType("string", "", nil)
+		return abi.Arguments{{Type: stringType}}
+	}()
+)
+
+// Caller executes read-only contract calls on one chain
+type Caller interface {
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+}
+
+// MetadataService resolves decimals, symbol and name of token contracts.
+// Lookups go through Redis, then the tokens table, and only then eth_call.
+type MetadataService struct {
+	unitOfWork repository.UnitOfWork
+	tokenRepo  repository.TokenRepository
+	redis      *cache.RedisClient
+	logger     *util.Logger
+
+	mu      sync.RWMutex
+	callers map[int64]Caller
+}
+
+func NewMetadataService(
+	unitOfWork repository.UnitOfWork,
+	tokenRepo repository.TokenRepository,
+	redis *cache.RedisClient,
+	logger *util.Logger,
+) *MetadataService {
+	return &MetadataService{
+		unitOfWork: unitOfWork,
+		tokenRepo:  tokenRepo,
+		redis:      redis,
+		logger:     logger,
+		callers:    make(map[int64]Caller),
+	}
+}
+
+// AddChain registers the caller used to resolve tokens on a chain
+func (s *MetadataService) AddChain(chainID int64, caller Caller) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.callers[chainID] = caller
+}
+
+// Enrich fills the metadata and formatted amount of every transfer. Tokens
+// that cannot be resolved right now are logged and left without metadata.
+func (s *MetadataService) Enrich(ctx context.Context, chainID int64, transfers []domain.TokenTransfer) {
+	resolved := make(map[string]*domain.Token)
+	for i := range transfers {
+		transfer := &transfers[i]
+
+		token, ok := resolved[transfer.TokenAddress]
+		if !ok {
+			var err error
+			token, err = s.Resolve(ctx, chainID, transfer.TokenAddress)
+			if err != nil {
+				s.logger.WithError(err).Warnf("[Tokens] failed to resolve token %s on chain %d",
+					transfer.TokenAddress, chainID)
+			}
+			resolved[transfer.TokenAddress] = token
+		}
+		if token == nil {
+			continue
+		}
+
+		transfer.TokenSymbol = token.Symbol
+		transfer.TokenName = token.Name
+		if transfer.Standard == domain.TokenStandardERC20 {
+			transfer.TokenDecimals = token.Decimals
+			if token.Decimals != nil && transfer.Value != nil {
+				formatted := domain.FormatUnits(transfer.Value, *token.Decimals)
+				transfer.FormattedValue = &formatted
+			}
+		}
+	}
+}
+
+// Resolve returns the metadata of a token contract
+func (s *MetadataService) Resolve(ctx context.Context, chainID int64, address string) (*domain.Token, error) {
+	address = strings.ToLower(address)
+
+	if token, err := s.redis.GetTokenMetadata(ctx, chainID, address); err != nil {
+		s.logger.WithError(err).Warn("[Tokens] failed to read token cache")
+	} else if token != nil {
+		return token, nil
+	}
+
+	token, err := s.tokenRepo.FindByAddress(ctx, chainID, address)
+	if err != nil {
+		return nil, err
+	}
+
+	if token == nil {
+		token, err = s.fetch(ctx, chainID, address)
+		if err != nil {
+			return nil, err
+		}
+		err = s.unitOfWork.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+			return s.tokenRepo.Upsert(ctx, tx, token)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.redis.SetTokenMetadata(ctx, token); err != nil {
+		s.logger.WithError(err).Warn("[Tokens] failed to cache token metadata")
+	}
+	return token, nil
+}
+
+// fetch reads the metadata from the chain. Getters that revert or return
+// nothing are recorded as absent; transport errors fail the lookup so it is
+// retried later instead of caching an incomplete result.
+func (s *MetadataService) fetch(ctx context.Context, chainID int64, address string) (*domain.Token, error) {
+	s.mu.RLock()
+	caller, ok := s.callers[chainID]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("no RPC client for chain %d", chainID)
+	}
+
+	contract := common.HexToAddress(address)
+	token := &domain.Token{ChainID: chainID, Address: address}
+
+	data, err := call(ctx, caller, contract, decimalsSelector)
+	if err != nil {
+		return nil, err
+	}
+	token.Decimals = decodeDecimals(data)
+
+	if data, err = call(ctx, caller, contract, symbolSelector); err != nil {
+		return nil, err
+	}
+	token.Symbol = decodeText(data)
+
+	if data, err = call(ctx, caller, contract, nameSelector); err != nil {
+		return nil, err
+	}
+	token.Name = decodeText(data)
+
+	return token, nil
+}
+
+// call runs a getter, treating a revert as an empty answer
+func call(ctx context.Context, caller Caller, contract common.Address, selector []byte) ([]byte, error) {
+	data, err := caller.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: selector}, nil)
+	if err != nil {
+		if errors.Is(err, client.ErrExecutionReverted) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("eth_call to %s failed: %w", contract.Hex(), err)
+	}
+	return data, nil
+}
+
+// decodeDecimals reads a uint8 return value; anything larger is not a valid ERC-20 answer
+func decodeDecimals(data []byte) *int {
+	if len(data) < 32 {
+		return nil
+	}
+	value := new(big.Int).SetBytes(data[:32])
+	if !value.IsUint64() || value.Uint64() > 255 {
+		return nil
+	}
+	decimals := int(value.Uint64())
+	return &decimals
+}
+
+// decodeText reads a string return value, falling back to the bytes32 that
+// legacy tokens such as MKR return
+func decodeText(data []byte) *string {
+	var text string
+	if values, err := stringResult.Unpack(data); err == nil && len(values) == 1 {
+		text, _ = values[0].(string)
+	} else if len(data) == 32 {
+		text = string(bytes.TrimRight(data, "\x00"))
+	}
+
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\x00", ""))
+	if text == "" || !utf8.ValidString(text) {
+		return nil
+	}
+	if len(text) > maxTextLength {
+		text = text[:maxTextLength]
+		for !utf8.ValidString(text) {
+			text = text[:len(text)-1]
+		}
+	}
+	return &text
+}
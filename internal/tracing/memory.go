@@ -0,0 +1,16 @@
+package tracing
+
+import (
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// InstallInMemory installs a tracer provider that records every span in
+// memory instead of exporting it, so tests can assert on the spans a block or
+// delivery produced. Spans are available as soon as they end.
+func InstallInMemory() *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	return exporter
+}
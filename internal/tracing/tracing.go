@@ -0,0 +1,93 @@
+// Package tracing sets up OpenTelemetry tracing. A trace follows a block from
+// its fetch through processing to every webhook delivery it causes: the span
+// context travels with the block event, and deliveries store it as a W3C
+// traceparent so the dispatcher continues the same trace.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"evm-tx-watcher/internal/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "evm-tx-watcher"
+
+var propagator = propagation.TraceContext{}
+
+// Init installs the global tracer provider of a process, exporting spans over
+// OTLP/HTTP. While tracing is disabled spans are not recorded. The returned
+// function flushes pending spans and stops the exporter.
+func Init(ctx context.Context, cfg config.TracingConfig, service string) (func(context.Context) error, error) {
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", service))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start starts a span as a child of the span in ctx, if any
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartQuery starts a span for a database statement, e.g. INSERT on transactions
+func StartQuery(ctx context.Context, operation, table string) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, operation+" "+table,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.operation", operation),
+			attribute.String("db.sql.table", table),
+		),
+	)
+}
+
+// End marks span as failed when err is set and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// TraceParent returns the W3C traceparent of the span in ctx, or nil when
+// there is no span to continue
+func TraceParent(ctx context.Context) *string {
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	if traceParent := carrier.Get("traceparent"); traceParent != "" {
+		return &traceParent
+	}
+	return nil
+}
+
+// ContextWithTraceParent returns ctx continuing the trace of a stored
+// traceparent; spans started from it become children of the stored span
+func ContextWithTraceParent(ctx context.Context, traceParent *string) context.Context {
+	if traceParent == nil {
+		return ctx
+	}
+	return propagator.Extract(ctx, propagation.MapCarrier{"traceparent": *traceParent})
+}
@@ -0,0 +1,353 @@
+package tracing_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"evm-tx-watcher/internal/blockchain/client"
+	"evm-tx-watcher/internal/blockchain/watcher"
+	"evm-tx-watcher/internal/cache"
+	"evm-tx-watcher/internal/config"
+	"evm-tx-watcher/internal/processor"
+	"evm-tx-watcher/internal/repository"
+	"evm-tx-watcher/internal/stream"
+	"evm-tx-watcher/internal/token"
+	"evm-tx-watcher/internal/tracing"
+	"evm-tx-watcher/internal/util"
+	"evm-tx-watcher/internal/webhook"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const chainID = 1337
+
+// fakeNode serves a single block over JSON-RPC as an EVM node would
+type fakeNode struct {
+	block    *types.Block
+	receipts []*types.Receipt
+	senders  []common.Address
+}
+
+func (n *fakeNode) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(chainID))
+}
+
+func (n *fakeNode) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(n.block.NumberU64())
+}
+
+func (n *fakeNode) GetBlockByNumber(number rpc.BlockNumber, full bool) (map[string]interface{}, error) {
+	data, err := json.Marshal(n.block.Header())
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	fields["uncles"] = []common.Hash{}
+
+	txs := make([]interface{}, 0, len(n.block.Transactions()))
+	for i, tx := range n.block.Transactions() {
+		if !full {
+			txs = append(txs, tx.Hash())
+			continue
+		}
+		data, err := json.Marshal(tx)
+		if err != nil {
+			return nil, err
+		}
+		var txFields map[string]interface{}
+		if err := json.Unmarshal(data, &txFields); err != nil {
+			return nil, err
+		}
+		txFields["blockHash"] = n.block.Hash()
+		txFields["blockNumber"] = (*hexutil.Big)(n.block.Number())
+		txFields["transactionIndex"] = hexutil.Uint64(i)
+		txFields["from"] = n.senders[i]
+		txs = append(txs, txFields)
+	}
+	fields["transactions"] = txs
+	return fields, nil
+}
+
+func (n *fakeNode) GetBlockReceipts(rpc.BlockNumberOrHash) ([]*types.Receipt, error) {
+	return n.receipts, nil
+}
+
+// newFakeNode builds block 100 with one transfer from a fresh key to recipient
+func newFakeNode(t *testing.T, recipient common.Address) *fakeNode {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := types.MustSignNewTx(key, types.LatestSignerForChainID(big.NewInt(chainID)), &types.LegacyTx{
+		To:       &recipient,
+		Value:    big.NewInt(1_000_000_000_000_000_000),
+		Gas:      21000,
+		GasPrice: big.NewInt(1_000_000_000),
+	})
+	receipt := &types.Receipt{
+		Type:              types.LegacyTxType,
+		Status:            types.ReceiptStatusSuccessful,
+		CumulativeGasUsed: 21000,
+		GasUsed:           21000,
+		TxHash:            tx.Hash(),
+		Logs:              []*types.Log{},
+	}
+
+	header := &types.Header{
+		ParentHash: common.HexToHash("0x01"),
+		Number:     big.NewInt(100),
+		GasLimit:   30_000_000,
+		GasUsed:    21000,
+		Time:       uint64(time.Now().Unix()),
+		Difficulty: big.NewInt(0),
+	}
+	block := types.NewBlock(header, &types.Body{Transactions: types.Transactions{tx}}, []*types.Receipt{receipt}, trie.NewStackTrie(nil))
+	receipt.BlockHash = block.Hash()
+	receipt.BlockNumber = block.Number()
+
+	return &fakeNode{
+		block:    block,
+		receipts: []*types.Receipt{receipt},
+		senders:  []common.Address{crypto.PubkeyToAddress(key.PublicKey)},
+	}
+}
+
+// capture is a sqlmock argument that matches anything and keeps the value
+type capture struct {
+	value driver.Value
+}
+
+func (c *capture) Match(v driver.Value) bool {
+	c.value = v
+	return true
+}
+
+func anyArgs(n int) []driver.Value {
+	args := make([]driver.Value, n)
+	for i := range args {
+		args[i] = sqlmock.AnyArg()
+	}
+	return args
+}
+
+// TestBlockTrace follows a block from its fetch by the watcher through the
+// processor to the dispatcher and checks every span joined the same trace
+func TestBlockTrace(t *testing.T) {
+	exporter := tracing.InstallInMemory()
+	logger := util.NewLogger("error", "text")
+	ctx := context.Background()
+
+	recipient := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	node := newFakeNode(t, recipient)
+	rpcServer := rpc.NewServer()
+	if err := rpcServer.RegisterName("eth", node); err != nil {
+		t.Fatal(err)
+	}
+	nodeServer := httptest.NewServer(rpcServer)
+	defer nodeServer.Close()
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+
+	mr := miniredis.RunT(t)
+	port, err := strconv.Atoi(mr.Port())
+	if err != nil {
+		t.Fatal(err)
+	}
+	redis, err := cache.NewRedisClient(&config.RedisConfig{Host: mr.Host(), Port: port})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer redis.Close()
+
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := sqlx.NewDb(mockDB, "postgres")
+	defer db.Close()
+
+	webhookID := uuid.New()
+	deliveryID, traceParent := &capture{}, &capture{}
+
+	mock.ExpectQuery(`FROM block_cursors`).
+		WillReturnRows(sqlmock.NewRows([]string{"chain_id", "network", "block_number", "block_hash", "updated_at"}))
+	mock.ExpectQuery(`FROM addresses a`).
+		WillReturnRows(sqlmock.NewRows([]string{"address", "chain_id", "is_active", "webhook_id", "webhook_url", "events", "user_id"}).
+			AddRow(strings.ToLower(recipient.Hex()), chainID, true, webhookID.String(), receiver.URL, "{}", nil))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM transactions`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec(`INSERT INTO transactions`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO webhook_deliveries`).
+		WithArgs(append([]driver.Value{deliveryID}, append(anyArgs(13), traceParent)...)...).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO block_cursors`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	network := config.NetworkConfig{
+		Name:               "testnet",
+		ChainID:            chainID,
+		HTTPURLs:           []string{nodeServer.URL},
+		HeadSource:         config.HeadSourcePoll,
+		PollInterval:       50 * time.Millisecond,
+		ConfirmationPolicy: config.ConfirmationDepth,
+	}
+	ethClient, err := client.New(network, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ethClient.Close()
+
+	// Fetch: the watcher picks up the head and hands the block over
+	unitOfWork := repository.NewUnitOfWork(db)
+	cursorRepo := repository.NewBlockCursorRepository(db)
+	queue := watcher.NewBlockQueue(network.Name, 1)
+	watchCtx, stopWatcher := context.WithCancel(ctx)
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- watcher.New(ethClient, cursorRepo, network, 0, logger).Start(watchCtx, queue)
+	}()
+
+	var event *watcher.BlockEvent
+	select {
+	case event = <-queue.Events():
+	case <-time.After(10 * time.Second):
+		t.Fatal("watcher did not release the block")
+	}
+	stopWatcher()
+	if err := <-stopped; err != nil {
+		t.Fatal(err)
+	}
+
+	// Process: the match is stored together with its delivery
+	deliveryRepo := repository.NewWebhookDeliveryRepository(db)
+	proc := processor.New(logger, unitOfWork,
+		repository.NewAddressRepository(db),
+		repository.NewTransactionRepository(db),
+		repository.NewTokenTransferRepository(db),
+		repository.NewInternalTransferRepository(db),
+		deliveryRepo,
+		cursorRepo,
+		redis,
+		token.NewMetadataService(unitOfWork, repository.NewTokenRepository(db), redis, logger),
+		stream.NewPublisher(redis, 100, logger),
+	)
+	if err := proc.HandleBlock(ctx, event); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, ok := traceParent.value.(string)
+	if !ok || stored == "" {
+		t.Fatalf("delivery was stored without a trace parent: %v", traceParent.value)
+	}
+
+	// Deliver: the dispatcher loads the delivery as stored and sends it
+	now := time.Now()
+	mock.ExpectQuery(`FROM webhook_deliveries`).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "webhook_id", "transaction_id", "payload", "status", "http_status_code",
+			"response_body", "error_message", "retry_count", "max_retries", "next_retry_at",
+			"delivered_at", "created_at", "updated_at", "trace_parent",
+		}).AddRow(deliveryID.value, webhookID.String(), uuid.NewString(), `{"event":"transaction.confirmed"}`, "pending", nil,
+			nil, nil, 0, 3, nil, nil, now, now, stored))
+	mock.ExpectQuery(`FROM webhooks`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "address_id", "url", "secret", "is_active", "events", "created_at", "updated_at"}).
+			AddRow(webhookID.String(), uuid.NewString(), receiver.URL, "secret", true, "{}", now, now))
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE webhook_deliveries`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO webhook_delivery_attempts`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	delivery, err := deliveryRepo.FindByID(ctx, uuid.MustParse(deliveryID.value.(string)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dispatcher := webhook.NewDispatcher(config.DispatcherConfig{Workers: 1, Timeout: 5 * time.Second, ConsumerName: "test"},
+		unitOfWork, deliveryRepo, repository.NewWebhookDeliveryAttemptRepository(db), repository.NewWebhookRepository(db),
+		redis, logger)
+	if err := dispatcher.Deliver(ctx, delivery); err != nil {
+		t.Fatal(err)
+	}
+	if delivery.Status != "delivered" {
+		t.Fatalf("delivery status = %s, want delivered", delivery.Status)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := spansByName(exporter.GetSpans())
+	root, ok := spans["watcher.block"]
+	if !ok {
+		t.Fatal("no watcher.block span recorded")
+	}
+	traceID := root.SpanContext.TraceID()
+
+	for _, name := range []string{
+		"client.GetBlockWithTransactions",
+		"eth_getBlockByNumber",
+		"eth_getBlockReceipts",
+		"processor.HandleBlock",
+		"processor.match",
+		"INSERT transactions",
+		"INSERT webhook_deliveries",
+		"UPSERT block_cursors",
+		"dispatcher.Deliver",
+		"dispatcher.send",
+		"UPDATE webhook_deliveries",
+		"INSERT webhook_delivery_attempts",
+	} {
+		span, ok := spans[name]
+		if !ok {
+			t.Errorf("no %s span recorded", name)
+			continue
+		}
+		if span.SpanContext.TraceID() != traceID {
+			t.Errorf("%s span is in trace %s, want %s", name, span.SpanContext.TraceID(), traceID)
+		}
+	}
+
+	// Processing continues the fetch, the delivery continues the processing
+	if parent := spans["processor.HandleBlock"].Parent.SpanID(); parent != root.SpanContext.SpanID() {
+		t.Errorf("processor.HandleBlock parent = %s, want watcher.block %s", parent, root.SpanContext.SpanID())
+	}
+	if parent := spans["dispatcher.Deliver"].Parent.SpanID(); parent != spans["processor.HandleBlock"].SpanContext.SpanID() {
+		t.Errorf("dispatcher.Deliver parent = %s, want processor.HandleBlock %s", parent, spans["processor.HandleBlock"].SpanContext.SpanID())
+	}
+}
+
+// spansByName indexes spans by name, keeping the first of each
+func spansByName(spans tracetest.SpanStubs) map[string]tracetest.SpanStub {
+	byName := make(map[string]tracetest.SpanStub, len(spans))
+	for _, span := range spans {
+		if _, ok := byName[span.Name]; !ok {
+			byName[span.Name] = span
+		}
+	}
+	return byName
+}
@@ -44,6 +44,8 @@ func (v *Validator) ParseValidationError(err error) map[string]string {
 				validationErrors[field] = field + " must be at least " + validationErr.Param() + " characters"
 			case "max":
 				validationErrors[field] = field + " must be at most " + validationErr.Param() + " characters"
+			case "oneof":
+				validationErrors[field] = field + " must be one of: " + validationErr.Param()
 			case "eth_addr":
 				validationErrors[field] = field + " must be a valid Ethereum address"
 			default:
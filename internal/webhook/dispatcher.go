@@ -0,0 +1,354 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"evm-tx-watcher/internal/cache"
+	"evm-tx-watcher/internal/config"
+	"evm-tx-watcher/internal/domain"
+	"evm-tx-watcher/internal/metrics"
+	"evm-tx-watcher/internal/repository"
+	"evm-tx-watcher/internal/tracing"
+	"evm-tx-watcher/internal/util"
+	"evm-tx-watcher/pkg/webhooksig"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// maxResponseBody caps how much of a receiver's response is stored
+	maxResponseBody = 4096
+	// dequeueTimeout bounds each blocking queue read so shutdown is noticed
+	dequeueTimeout = 5 * time.Second
+	// claimBatchSize bounds how many stale queue entries are taken over at once
+	claimBatchSize = 100
+)
+
+// Dispatcher takes pending webhook deliveries off the Redis queue, POSTs their
+// signed payload and records the outcome on the delivery row. Queue entries are
+// acknowledged only after the outcome is recorded; entries a crashed dispatcher
+// left behind are claimed by another one once they have been idle for claimIdle.
+type Dispatcher struct {
+	unitOfWork    repository.UnitOfWork
+	deliveryRepo  repository.WebhookDeliveryRepository
+	attemptRepo   repository.WebhookDeliveryAttemptRepository
+	webhookRepo   repository.WebhookRepository
+	redis         *cache.RedisClient
+	httpClient    *http.Client
+	workers       int
+	backoff       Backoff
+	consumer      string
+	claimIdle     time.Duration
+	claimInterval time.Duration
+	logger        *util.Logger
+}
+
+func NewDispatcher(
+	cfg config.DispatcherConfig,
+	unitOfWork repository.UnitOfWork,
+	deliveryRepo repository.WebhookDeliveryRepository,
+	attemptRepo repository.WebhookDeliveryAttemptRepository,
+	webhookRepo repository.WebhookRepository,
+	redis *cache.RedisClient,
+	logger *util.Logger,
+) *Dispatcher {
+	consumer := cfg.ConsumerName
+	if consumer == "" {
+		// A restarted dispatcher keeps its name and picks up its own unacknowledged entries
+		consumer, _ = os.Hostname()
+	}
+
+	return &Dispatcher{
+		unitOfWork:    unitOfWork,
+		deliveryRepo:  deliveryRepo,
+		attemptRepo:   attemptRepo,
+		webhookRepo:   webhookRepo,
+		redis:         redis,
+		httpClient:    &http.Client{Timeout: cfg.Timeout},
+		workers:       cfg.Workers,
+		backoff:       Backoff{Base: cfg.RetryBaseDelay, Max: cfg.RetryMaxDelay},
+		consumer:      consumer,
+		claimIdle:     cfg.ClaimIdle,
+		claimInterval: cfg.ClaimInterval,
+		logger:        logger,
+	}
+}
+
+// Start runs the delivery workers until ctx is cancelled
+func (d *Dispatcher) Start(ctx context.Context) error {
+	moved, err := d.redis.EnsureWebhookQueue(ctx)
+	if err != nil {
+		return err
+	}
+	if moved > 0 {
+		d.logger.Infof("[Dispatcher] Moved %d deliveries from the legacy queue", moved)
+	}
+
+	d.logger.Infof("[Dispatcher] Starting %d delivery workers as consumer %s", d.workers, d.consumer)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		d.claim(ctx)
+	}()
+	for i := 0; i < d.workers; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			d.work(ctx, id)
+		}(i)
+	}
+
+	wg.Wait()
+	d.logger.Info("[Dispatcher] Stopped")
+	return nil
+}
+
+func (d *Dispatcher) work(ctx context.Context, id int) {
+	for ctx.Err() == nil {
+		queued, err := d.redis.DequeueWebhookDeliveries(ctx, d.consumer, 1, dequeueTimeout)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			d.logger.WithError(err).Errorf("[Dispatcher] worker %d failed to dequeue delivery", id)
+			time.Sleep(time.Second)
+			continue
+		}
+
+		for _, entry := range queued {
+			d.handle(ctx, entry)
+		}
+	}
+}
+
+// claim periodically takes over queue entries that stayed unacknowledged for
+// claimIdle and delivers them with the same concurrency as the queue workers
+func (d *Dispatcher) claim(ctx context.Context) {
+	ticker := time.NewTicker(d.claimInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			stale, err := d.redis.ClaimStaleWebhookDeliveries(ctx, d.consumer, d.claimIdle, claimBatchSize)
+			if err != nil {
+				d.logger.WithError(err).Error("[Dispatcher] failed to claim stale deliveries")
+				continue
+			}
+			if len(stale) == 0 {
+				continue
+			}
+			d.logger.Warnf("[Dispatcher] claimed %d stale deliveries", len(stale))
+
+			sem := make(chan struct{}, d.workers)
+			var wg sync.WaitGroup
+			for _, entry := range stale {
+				wg.Add(1)
+				sem <- struct{}{}
+				go func(entry cache.QueuedDelivery) {
+					defer wg.Done()
+					defer func() { <-sem }()
+					d.handle(ctx, entry)
+				}(entry)
+			}
+			wg.Wait()
+		}
+	}
+}
+
+// handle claims and delivers a queue entry and acknowledges it once the outcome
+// is recorded. Entries that fail before that stay pending and are claimed again.
+func (d *Dispatcher) handle(ctx context.Context, entry cache.QueuedDelivery) {
+	if entry.DeliveryID == uuid.Nil {
+		d.logger.Warnf("[Dispatcher] dropping malformed queue entry %s", entry.MessageID)
+		d.ack(ctx, entry)
+		return
+	}
+
+	// Postgres is the source of truth; the queue only carries the ID and lease
+	seen := entry.Lease
+	if seen == nil {
+		// Entries queued before leases were recorded take the lease the row holds now
+		delivery, err := d.deliveryRepo.FindByID(ctx, entry.DeliveryID)
+		if err != nil {
+			d.logger.WithError(err).Errorf("[Dispatcher] failed to load delivery %s", entry.DeliveryID)
+			return
+		}
+		if delivery == nil {
+			d.ack(ctx, entry)
+			return
+		}
+		seen = delivery.NextRetryAt
+	}
+
+	// The claim fails for deliveries that were resolved already, e.g. by a
+	// dispatcher that crashed after recording the outcome but before
+	// acknowledging it, and for ones another dispatcher or the retry scheduler
+	// took over. Either way the entry is done.
+	delivery, err := d.deliveryRepo.Claim(ctx, entry.DeliveryID, seen, time.Now().Add(retryLease))
+	if err != nil {
+		d.logger.WithError(err).Errorf("[Dispatcher] failed to claim delivery %s", entry.DeliveryID)
+		return
+	}
+	if delivery != nil {
+		if err := d.Deliver(ctx, delivery); err != nil {
+			d.logger.WithError(err).Errorf("[Dispatcher] failed to record delivery %s", delivery.ID)
+			return
+		}
+	}
+	d.ack(ctx, entry)
+}
+
+func (d *Dispatcher) ack(ctx context.Context, entry cache.QueuedDelivery) {
+	if err := d.redis.AckWebhookDelivery(ctx, entry.MessageID); err != nil {
+		d.logger.WithError(err).Errorf("[Dispatcher] failed to acknowledge delivery %s", entry.DeliveryID)
+	}
+}
+
+// Deliver sends a delivery to its webhook and records the attempt. The returned
+// error only reports failures to record the outcome; an unsuccessful HTTP call
+// is stored on the delivery itself. The span joins the trace of the block that
+// caused the delivery.
+func (d *Dispatcher) Deliver(ctx context.Context, delivery *domain.WebhookDelivery) (err error) {
+	ctx, span := tracing.Start(tracing.ContextWithTraceParent(ctx, delivery.TraceParent), "dispatcher.Deliver",
+		attribute.String("delivery.id", delivery.ID.String()),
+		attribute.String("webhook.id", delivery.WebhookID.String()),
+		attribute.Int("delivery.retry_count", delivery.RetryCount),
+	)
+	defer func() {
+		span.SetAttributes(attribute.String("delivery.status", delivery.Status))
+		tracing.End(span, err)
+	}()
+
+	webhook, err := d.webhookRepo.FindByID(ctx, delivery.WebhookID)
+	if err != nil {
+		return fmt.Errorf("failed to load webhook %s: %w", delivery.WebhookID, err)
+	}
+
+	started := time.Now()
+	var statusCode int
+	var responseBody string
+	if webhook == nil {
+		err = fmt.Errorf("webhook %s no longer exists", delivery.WebhookID)
+	} else if !webhook.IsActive {
+		err = fmt.Errorf("webhook %s is disabled", delivery.WebhookID)
+	} else {
+		statusCode, responseBody, err = d.send(ctx, webhook, delivery)
+	}
+
+	attempt := &domain.WebhookDeliveryAttempt{
+		ID:         uuid.New(),
+		DeliveryID: delivery.ID,
+		DurationMs: time.Since(started).Milliseconds(),
+		CreatedAt:  started,
+	}
+	if statusCode != 0 {
+		delivery.HTTPStatusCode = &statusCode
+		delivery.ResponseBody = &responseBody
+		attempt.HTTPStatusCode = &statusCode
+		attempt.ResponseBody = &responseBody
+	}
+	if err != nil {
+		message := err.Error()
+		attempt.ErrorMessage = &message
+	}
+
+	if err == nil {
+		now := time.Now()
+		delivery.Status = string(domain.WebhookDeliveryStatusDelivered)
+		delivery.DeliveredAt = &now
+		delivery.NextRetryAt = nil
+		delivery.ErrorMessage = nil
+		d.logger.Infof("[Dispatcher] delivered %s to webhook %s status=%d in %s",
+			delivery.ID, delivery.WebhookID, statusCode, time.Since(started))
+	} else {
+		message := err.Error()
+		delivery.ErrorMessage = &message
+
+		if delivery.RetryCount >= delivery.MaxRetries {
+			delivery.Status = string(domain.WebhookDeliveryStatusMaxRetriesExceeded)
+			delivery.NextRetryAt = nil
+			d.logger.WithError(err).Errorf("[Dispatcher] delivery %s to webhook %s failed after %d retries, giving up",
+				delivery.ID, delivery.WebhookID, delivery.RetryCount)
+		} else {
+			next := time.Now().Add(d.backoff.Delay(delivery.RetryCount))
+			delivery.Status = string(domain.WebhookDeliveryStatusFailed)
+			delivery.NextRetryAt = &next
+			d.logger.WithError(err).Warnf("[Dispatcher] delivery %s to webhook %s failed, retry %d/%d at %s",
+				delivery.ID, delivery.WebhookID, delivery.RetryCount+1, delivery.MaxRetries, next.Format(time.RFC3339))
+		}
+	}
+
+	metrics.WebhookDeliveries.WithLabelValues(delivery.Status).Inc()
+	metrics.WebhookDeliveryDuration.WithLabelValues(delivery.Status).Observe(float64(attempt.DurationMs) / 1000)
+
+	return d.unitOfWork.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		if err := d.deliveryRepo.Update(ctx, tx, delivery); err != nil {
+			return err
+		}
+		return d.attemptRepo.Create(ctx, tx, attempt)
+	})
+}
+
+// send POSTs the signed payload and returns the response status and a truncated body
+func (d *Dispatcher) send(ctx context.Context, webhook *domain.Webhook, delivery *domain.WebhookDelivery) (statusCode int, responseBody string, err error) {
+	ctx, span := tracing.Start(ctx, "dispatcher.send", attribute.String("http.request.method", http.MethodPost))
+	defer func() {
+		span.SetAttributes(attribute.Int("http.response.status_code", statusCode))
+		tracing.End(span, err)
+	}()
+
+	body := []byte(delivery.Payload)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to build request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "evm-tx-watcher-webhook")
+	req.Header.Set(webhooksig.DeliveryIDHeader, delivery.ID.String())
+	req.Header.Set(webhooksig.SignatureHeader, webhooksig.Sign(webhook.Secret, time.Now(), body))
+	if event := payloadEvent(body); event != "" {
+		req.Header.Set(webhooksig.EventHeader, event)
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return 0, "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, string(respBody), fmt.Errorf("receiver responded with status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, string(respBody), nil
+}
+
+// payloadEvent extracts the event type from a stored payload
+func payloadEvent(body []byte) string {
+	var payload struct {
+		Event string `json:"event"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return ""
+	}
+	return payload.Event
+}
@@ -0,0 +1,146 @@
+package webhook
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"evm-tx-watcher/internal/config"
+	"evm-tx-watcher/internal/domain"
+	"evm-tx-watcher/internal/repository"
+	"evm-tx-watcher/internal/util"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// retryLease is how long a claimed delivery may stay unresolved before the
+// retry scheduler is allowed to claim it again
+const retryLease = 5 * time.Minute
+
+// Backoff computes jittered exponential retry delays
+type Backoff struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+// Delay returns the wait before retry number attempt+1: Base doubled per
+// previous attempt and capped at Max, randomised to between half and the full
+// value so receivers that failed together are not retried together.
+func (b Backoff) Delay(attempt int) time.Duration {
+	delay := b.Max
+	if attempt < 32 {
+		if d := b.Base << uint(attempt); d > 0 && d < b.Max {
+			delay = d
+		}
+	}
+
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+// RetryScheduler polls failed deliveries whose backoff elapsed and redelivers
+// them. Rows are claimed with FOR UPDATE SKIP LOCKED, so any number of
+// schedulers can run side by side without sending a delivery twice.
+type RetryScheduler struct {
+	dispatcher   *Dispatcher
+	unitOfWork   repository.UnitOfWork
+	deliveryRepo repository.WebhookDeliveryRepository
+	interval     time.Duration
+	batchSize    int
+	logger       *util.Logger
+}
+
+func NewRetryScheduler(
+	cfg config.DispatcherConfig,
+	dispatcher *Dispatcher,
+	unitOfWork repository.UnitOfWork,
+	deliveryRepo repository.WebhookDeliveryRepository,
+	logger *util.Logger,
+) *RetryScheduler {
+	return &RetryScheduler{
+		dispatcher:   dispatcher,
+		unitOfWork:   unitOfWork,
+		deliveryRepo: deliveryRepo,
+		interval:     cfg.RetryPollInterval,
+		batchSize:    cfg.RetryBatchSize,
+		logger:       logger,
+	}
+}
+
+// Start polls for due retries until ctx is cancelled
+func (s *RetryScheduler) Start(ctx context.Context) error {
+	s.logger.Infof("[RetryScheduler] Polling due retries every %s", s.interval)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("[RetryScheduler] Stopped")
+			return nil
+		case <-ticker.C:
+			// Keep draining while full batches come back
+			for ctx.Err() == nil {
+				claimed, err := s.poll(ctx)
+				if err != nil {
+					s.logger.WithError(err).Error("[RetryScheduler] Failed to process due retries")
+					break
+				}
+				if claimed < s.batchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// poll claims one batch of due deliveries and redelivers them
+func (s *RetryScheduler) poll(ctx context.Context) (int, error) {
+	var claimed []*domain.WebhookDelivery
+
+	err := s.unitOfWork.WithTransaction(ctx, func(tx *sqlx.Tx) error {
+		due, err := s.deliveryRepo.FindPendingRetries(ctx, tx, s.batchSize)
+		if err != nil {
+			return err
+		}
+
+		lease := time.Now().Add(retryLease)
+		for _, delivery := range due {
+			delivery.RetryCount++
+			delivery.Status = string(domain.WebhookDeliveryStatusPending)
+			delivery.NextRetryAt = &lease
+			if err := s.deliveryRepo.Update(ctx, tx, delivery); err != nil {
+				return err
+			}
+		}
+		claimed = due
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if len(claimed) > 0 {
+		s.logger.Infof("[RetryScheduler] Retrying %d deliveries", len(claimed))
+	}
+
+	// Redeliver with the same concurrency as the queue workers
+	sem := make(chan struct{}, s.dispatcher.workers)
+	var wg sync.WaitGroup
+	for _, delivery := range claimed {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(delivery *domain.WebhookDelivery) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := s.dispatcher.Deliver(ctx, delivery); err != nil {
+				s.logger.WithError(err).Errorf("[RetryScheduler] Failed to record retry of %s", delivery.ID)
+			}
+		}(delivery)
+	}
+	wg.Wait()
+
+	return len(claimed), nil
+}
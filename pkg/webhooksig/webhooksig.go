@@ -0,0 +1,145 @@
+// Package webhooksig signs and verifies evm-tx-watcher webhook requests.
+//
+// Every delivery carries a signature header of the form
+//
+//	X-Webhook-Signature: t=1700000000,v1=5257a869e7ecebeda32affa62cdca3fa51cad7e77a0e56ff536d0ce8e108d8bd
+//
+// where v1 is the hex encoded HMAC-SHA256 of "<t>.<raw body>" keyed with the
+// webhook secret. Receivers should verify the signature against the raw body
+// and reject requests whose timestamp is outside their tolerance, which stops
+// captured requests from being replayed later.
+package webhooksig
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// SignatureHeader carries the timestamped signature of the request body
+	SignatureHeader = "X-Webhook-Signature"
+	// DeliveryIDHeader carries the delivery ID, stable across retries of the same delivery
+	DeliveryIDHeader = "X-Webhook-Delivery"
+	// EventHeader carries the event type of the payload
+	EventHeader = "X-Webhook-Event"
+
+	// DefaultTolerance is the maximum accepted age of a signature
+	DefaultTolerance = 5 * time.Minute
+)
+
+var (
+	ErrMissingSignature = errors.New("webhooksig: missing signature header")
+	ErrInvalidHeader    = errors.New("webhooksig: malformed signature header")
+	ErrSignatureExpired = errors.New("webhooksig: signature timestamp outside tolerance")
+	ErrSignatureInvalid = errors.New("webhooksig: signature mismatch")
+)
+
+// Sign returns the signature header value for body at the given time
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	t := timestamp.Unix()
+	return fmt.Sprintf("t=%d,v1=%s", t, hex.EncodeToString(computeMAC(secret, t, body)))
+}
+
+// Verify checks a signature header value against body. Signatures older or
+// newer than tolerance relative to now are rejected; a zero tolerance uses
+// DefaultTolerance.
+func Verify(secret, header string, body []byte, tolerance time.Duration) error {
+	return verifyAt(secret, header, body, tolerance, time.Now())
+}
+
+// VerifyRequest reads the request body and verifies its signature header.
+// The body is returned so the caller can decode it; r.Body is consumed.
+func VerifyRequest(r *http.Request, secret string, tolerance time.Duration) ([]byte, error) {
+	header := r.Header.Get(SignatureHeader)
+	if header == "" {
+		return nil, ErrMissingSignature
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("webhooksig: failed to read body: %w", err)
+	}
+
+	if err := Verify(secret, header, body, tolerance); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+func verifyAt(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	if header == "" {
+		return ErrMissingSignature
+	}
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+
+	timestamp, signatures, err := parseHeader(header)
+	if err != nil {
+		return err
+	}
+
+	age := now.Sub(time.Unix(timestamp, 0))
+	if age > tolerance || age < -tolerance {
+		return ErrSignatureExpired
+	}
+
+	expected := computeMAC(secret, timestamp, body)
+	for _, signature := range signatures {
+		if hmac.Equal(expected, signature) {
+			return nil
+		}
+	}
+	return ErrSignatureInvalid
+}
+
+// parseHeader extracts the timestamp and every v1 signature from a header value.
+// Several v1 entries may be present while a secret is being rotated.
+func parseHeader(header string) (int64, [][]byte, error) {
+	var (
+		timestamp  int64
+		signatures [][]byte
+	)
+
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return 0, nil, ErrInvalidHeader
+		}
+
+		switch key {
+		case "t":
+			t, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return 0, nil, ErrInvalidHeader
+			}
+			timestamp = t
+		case "v1":
+			signature, err := hex.DecodeString(value)
+			if err != nil {
+				return 0, nil, ErrInvalidHeader
+			}
+			signatures = append(signatures, signature)
+		}
+	}
+
+	if timestamp == 0 || len(signatures) == 0 {
+		return 0, nil, ErrInvalidHeader
+	}
+	return timestamp, signatures, nil
+}
+
+func computeMAC(secret string, timestamp int64, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return mac.Sum(nil)
+}